@@ -7,16 +7,24 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/manzanita-research/chaparral/internal/budget"
+	"github.com/manzanita-research/chaparral/internal/compare"
 	"github.com/manzanita-research/chaparral/internal/config"
 	"github.com/manzanita-research/chaparral/internal/discovery"
 	"github.com/manzanita-research/chaparral/internal/generator"
 	"github.com/manzanita-research/chaparral/internal/linker"
 	"github.com/manzanita-research/chaparral/internal/marketplace"
+	"github.com/manzanita-research/chaparral/internal/mcp"
 	"github.com/manzanita-research/chaparral/internal/publisher"
+	"github.com/manzanita-research/chaparral/internal/render"
+	"github.com/manzanita-research/chaparral/internal/skillmeta"
+	"github.com/manzanita-research/chaparral/internal/textdiff"
 	"github.com/manzanita-research/chaparral/internal/tui"
 	"github.com/manzanita-research/chaparral/internal/validator"
+	"gopkg.in/yaml.v3"
 )
 
 func main() {
@@ -44,6 +52,10 @@ func main() {
 		runPublish(basePath)
 	case "unlink":
 		runUnlink(basePath)
+	case "budget":
+		runBudget(basePath)
+	case "skill", "skills":
+		runSkill(basePath)
 	case "help", "--help", "-h":
 		printHelp()
 	default:
@@ -135,15 +147,24 @@ func runStatus(basePath string) {
 		for _, skill := range skillOrder {
 			sts := skillMap[skill]
 			if skill == "CLAUDE.md" {
-				fmt.Printf("  %s CLAUDE.md — %s\n", stateIcon(sts[0].State), sts[0].State)
+				state := sts[0].State
+				if sts[0].Detail != "" {
+					state += " (" + sts[0].Detail + ")"
+				}
+				fmt.Printf("  %s CLAUDE.md — %s\n", stateIcon(sts[0].State), state)
 				continue
 			}
 
-			var linked, missing []string
+			var linked, missing, skipped []string
 			for _, st := range sts {
-				if st.State == "linked" {
+				switch {
+				case st.State == "linked+overlay":
+					linked = append(linked, st.Repo+" (overlay)")
+				case st.IsLinked():
 					linked = append(linked, st.Repo)
-				} else {
+				case st.State == "skipped":
+					skipped = append(skipped, fmt.Sprintf("%s (%s)", st.Repo, st.Detail))
+				default:
 					missing = append(missing, st.Repo)
 				}
 			}
@@ -155,9 +176,25 @@ func runStatus(basePath string) {
 			if len(missing) > 0 {
 				parts = append(parts, fmt.Sprintf("missing: %s", strings.Join(missing, ", ")))
 			}
+			if len(skipped) > 0 {
+				parts = append(parts, fmt.Sprintf("skipped: %s", strings.Join(skipped, ", ")))
+			}
 			fmt.Println(strings.Join(parts, "  "))
 		}
 
+		// Show rendered templates, including a templated claude_md
+		templateStatuses, err := render.StatusOrg(org)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  templates: %v\n", err)
+		} else if len(templateStatuses) > 0 {
+			printTemplateStatus(templateStatuses)
+		}
+
+		// Show managed MCP servers
+		if len(org.Manifest.MCP.Servers) > 0 {
+			printMCPStatus(mcp.StatusOrg(org))
+		}
+
 		// Show marketplace plugins per repo
 		if pluginErr != nil {
 			fmt.Printf("  marketplace plugins: could not load (%v)\n", pluginErr)
@@ -204,6 +241,89 @@ func runStatus(basePath string) {
 	}
 }
 
+// printTemplateStatus prints one line per rendered file, grouping repos by state.
+func printTemplateStatus(statuses []render.FileStatus) {
+	fmt.Println("  templates")
+
+	byPath := make(map[string]map[string][]string)
+	details := make(map[string]string)
+	var pathOrder []string
+	for _, st := range statuses {
+		if _, seen := byPath[st.Path]; !seen {
+			pathOrder = append(pathOrder, st.Path)
+			byPath[st.Path] = make(map[string][]string)
+		}
+		byPath[st.Path][st.State] = append(byPath[st.Path][st.State], st.Repo)
+		if st.State == "error" && details[st.Path] == "" {
+			details[st.Path] = st.Detail
+		}
+	}
+
+	for _, path := range pathOrder {
+		repos := byPath[path]
+		worst := "rendered"
+		for _, state := range []string{"error", "conflict", "outdated", "missing"} {
+			if len(repos[state]) > 0 {
+				worst = state
+				break
+			}
+		}
+
+		parts := []string{fmt.Sprintf("    %s %s", stateIcon(worst), path)}
+		for _, state := range []string{"rendered", "outdated", "missing", "conflict", "error"} {
+			if len(repos[state]) > 0 {
+				parts = append(parts, fmt.Sprintf("%s: %s", state, strings.Join(repos[state], ", ")))
+			}
+		}
+		if d := details[path]; d != "" {
+			parts = append(parts, "("+d+")")
+		}
+		fmt.Println(strings.Join(parts, "  "))
+	}
+}
+
+// printMCPStatus prints one line per managed MCP server, grouping repos by state.
+func printMCPStatus(statuses []mcp.ServerStatus) {
+	fmt.Println("  mcp servers")
+
+	byServer := make(map[string][]mcp.ServerStatus)
+	var serverOrder []string
+	for _, st := range statuses {
+		if _, seen := byServer[st.Server]; !seen {
+			serverOrder = append(serverOrder, st.Server)
+		}
+		byServer[st.Server] = append(byServer[st.Server], st)
+	}
+
+	for _, server := range serverOrder {
+		sts := byServer[server]
+		if sts[0].Repo == "(org)" {
+			fmt.Printf("    %s %s — %s\n", stateIcon("invalid"), server, sts[0].Detail)
+			continue
+		}
+
+		repos := map[string][]string{}
+		for _, st := range sts {
+			repos[st.State] = append(repos[st.State], st.Repo)
+		}
+		worst := "present"
+		for _, state := range []string{"invalid", "drifted", "missing"} {
+			if len(repos[state]) > 0 {
+				worst = state
+				break
+			}
+		}
+
+		parts := []string{fmt.Sprintf("    %s %s", stateIcon(worst), server)}
+		for _, state := range []string{"present", "missing", "drifted", "invalid"} {
+			if len(repos[state]) > 0 {
+				parts = append(parts, fmt.Sprintf("%s: %s", state, strings.Join(repos[state], ", ")))
+			}
+		}
+		fmt.Println(strings.Join(parts, "  "))
+	}
+}
+
 func pluginIcon(installed, enabled bool) string {
 	if installed && enabled {
 		return "✓"
@@ -215,47 +335,126 @@ func pluginIcon(installed, enabled bool) string {
 }
 
 func runValidate(basePath string) {
+	fix := false
+	dryRun := false
+	for _, arg := range os.Args[2:] {
+		switch arg {
+		case "--rules":
+			printRules()
+			return
+		case "--fix":
+			fix = true
+		case "--dry-run":
+			dryRun = true
+		}
+	}
+	if dryRun && !fix {
+		fmt.Fprintln(os.Stderr, "--dry-run only makes sense with --fix")
+		os.Exit(1)
+	}
+
 	orgs := loadOrgs(basePath)
 	hasErrors := false
 
 	for _, org := range orgs {
 		fmt.Printf("%s (%s/)\n", org.Name, filepath.Base(org.Path))
 
+		if fix {
+			applyFixes(org, dryRun)
+		}
+
 		results, err := validator.ValidateOrg(org)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "  %v\n", err)
 			hasErrors = true
 			continue
 		}
+		claudeMD, err := validator.ValidateClaudeMD(org)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  %v\n", err)
+			hasErrors = true
+			continue
+		}
 
 		if len(results) == 0 {
 			fmt.Println("  no skills found")
-			fmt.Println()
-			continue
 		}
+		skillsValid := printValidation(results)
+		claudeMDValid := printValidation(claudeMD)
+		if !skillsValid || !claudeMDValid {
+			hasErrors = true
+		}
+		fmt.Println()
+	}
 
-		for _, r := range results {
-			if r.IsValid() && len(r.Warnings) == 0 {
-				fmt.Printf("  ✓ %s\n", r.Skill)
-			} else if r.IsValid() {
-				fmt.Printf("  ~ %s\n", r.Skill)
-			} else {
-				fmt.Printf("  ✕ %s\n", r.Skill)
-				hasErrors = true
-			}
+	if hasErrors {
+		os.Exit(1)
+	}
+}
+
+// printValidation prints each result and its findings, and reports whether
+// none of them had errors.
+func printValidation(results []validator.ValidationResult) bool {
+	valid := true
+	for _, r := range results {
+		if r.IsValid() && len(r.Warnings) == 0 {
+			fmt.Printf("  ✓ %s\n", r.Skill)
+		} else if r.IsValid() {
+			fmt.Printf("  ~ %s\n", r.Skill)
+		} else {
+			fmt.Printf("  ✕ %s\n", r.Skill)
+			valid = false
+		}
 
-			for _, e := range r.Errors {
-				fmt.Printf("    ✕ %s\n", e)
+		for _, f := range r.Findings {
+			icon := "~"
+			if f.Severity == validator.SeverityError {
+				icon = "✕"
 			}
-			for _, w := range r.Warnings {
-				fmt.Printf("    ~ %s\n", w)
+			fmt.Printf("    %s %s [%s]\n", icon, f.Message, f.Rule)
+		}
+	}
+	return valid
+}
+
+// applyFixes prints a diff for each safe fix in an org and, unless dryRun is
+// set, writes it. Validation then runs against the fixed files.
+func applyFixes(org config.Org, dryRun bool) {
+	fixes, err := validator.FixOrg(org)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  %v\n", err)
+		return
+	}
+	if len(fixes) == 0 {
+		fmt.Println("  nothing to fix")
+		return
+	}
+
+	verb := "fixed"
+	if dryRun {
+		verb = "would fix"
+	}
+	for _, f := range fixes {
+		if !dryRun {
+			if err := f.Apply(); err != nil {
+				fmt.Fprintf(os.Stderr, "  ✕ %s — %v\n", f.Skill, err)
+				continue
 			}
 		}
-		fmt.Println()
+		fmt.Printf("  * %s %s: %s\n", verb, f.Skill, strings.Join(f.Changes, ", "))
+		rel := f.Skill + "/SKILL.md"
+		diff := textdiff.Unified("a/"+rel, "b/"+rel, string(f.Before), string(f.After))
+		for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+			fmt.Printf("    %s\n", line)
+		}
 	}
+	fmt.Println()
+}
 
-	if hasErrors {
-		os.Exit(1)
+// printRules lists every validation rule with its default severity.
+func printRules() {
+	for _, r := range validator.Rules() {
+		fmt.Printf("  %-22s %-8s %s\n", r.ID, r.Severity, r.Summary)
 	}
 }
 
@@ -285,9 +484,16 @@ func runGenerate(basePath string) {
 			continue
 		}
 
-		brandRepoPath := filepath.Join(org.Path, org.BrandRepo)
-
 		for _, skill := range skills {
+			internal, err := generator.SkillIsInternal(org, skill)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "  %s — %v\n", skill.Name, err)
+				continue
+			}
+			if internal {
+				fmt.Printf("  %s — internal, not published\n\n", skill.Name)
+				continue
+			}
 			data, err := generator.GeneratePluginJSON(skill)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "  %s/plugin.json — %v\n", skill.Name, err)
@@ -297,7 +503,9 @@ func runGenerate(basePath string) {
 			// Apply real next version (same logic as publish)
 			var manifest generator.PluginManifest
 			if err := json.Unmarshal(data, &manifest); err == nil {
-				manifest.Version = publisher.BumpVersion(brandRepoPath, org.Manifest.SkillsDir, skill.Name)
+				if version, err := publisher.SkillVersion(org, skill, publisher.BumpPatch); err == nil {
+					manifest.Version = version
+				}
 				if bumped, err := json.MarshalIndent(manifest, "", "  "); err == nil {
 					data = bumped
 				}
@@ -321,7 +529,9 @@ func runGenerate(basePath string) {
 					for i, plugin := range mkt.Plugins {
 						for _, skill := range skills {
 							if plugin.Name == skill.Name || filepath.Base(plugin.Source) == skill.Name {
-								mkt.Plugins[i].Version = publisher.BumpVersion(brandRepoPath, org.Manifest.SkillsDir, skill.Name)
+								if version, err := publisher.SkillVersion(org, skill, publisher.BumpPatch); err == nil {
+									mkt.Plugins[i].Version = version
+								}
 								break
 							}
 						}
@@ -341,6 +551,46 @@ func runGenerate(basePath string) {
 	}
 }
 
+func runBudget(basePath string) {
+	orgs := loadOrgs(basePath)
+
+	for _, org := range orgs {
+		fmt.Printf("%s (%s/)\n", org.Name, filepath.Base(org.Path))
+
+		report, err := budget.ReportOrg(org)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  %v\n", err)
+			continue
+		}
+		if len(report.Skills) == 0 {
+			fmt.Println("  no skills found")
+			fmt.Println()
+			continue
+		}
+
+		fmt.Printf("  %-24s %8s %8s\n", "skills", "always", "on use")
+		for _, s := range report.Skills {
+			fmt.Printf("    %-22s %8d %8d\n", s.Skill, s.MetadataTokens, s.BodyTokens)
+		}
+
+		fmt.Println("  repos")
+		for _, r := range report.Repos {
+			icon := "✓"
+			budgetNote := ""
+			if report.Budget > 0 {
+				budgetNote = fmt.Sprintf(" (budget %d)", report.Budget)
+			}
+			if r.OverBudget {
+				icon = "✕"
+				budgetNote = fmt.Sprintf(" — over budget of %d", report.Budget)
+			}
+			fmt.Printf("    %s %s  %d skills, ~%d tokens always loaded%s, ~%d more on use\n",
+				icon, r.Repo, len(r.Skills), r.MetadataTokens, budgetNote, r.BodyTokens)
+		}
+		fmt.Println()
+	}
+}
+
 func runUnlink(basePath string) {
 	orgs := loadOrgs(basePath)
 
@@ -363,18 +613,217 @@ func runUnlink(basePath string) {
 	}
 }
 
+func runSkill(basePath string) {
+	sub := ""
+	if len(os.Args) > 2 {
+		sub = os.Args[2]
+	}
+	switch sub {
+	case "set":
+		runSkillSet(basePath)
+	case "compare":
+		runSkillCompare(basePath)
+	default:
+		fmt.Fprintln(os.Stderr, "usage: chaparral skill set <skill> key=value [key=value ...]")
+		fmt.Fprintln(os.Stderr, "       chaparral skills compare [--threshold=0.8] [--no-diff]")
+		os.Exit(1)
+	}
+}
+
+func runSkillSet(basePath string) {
+	if len(os.Args) < 5 {
+		fmt.Fprintln(os.Stderr, "usage: chaparral skill set <skill> key=value [key=value ...]")
+		os.Exit(1)
+	}
+
+	skill, err := findSkill(loadOrgs(basePath), os.Args[3])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var fields []skillmeta.Field
+	for _, arg := range os.Args[4:] {
+		field, err := parseField(arg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		fields = append(fields, field)
+	}
+
+	path := filepath.Join(skill.Path, "SKILL.md")
+	if err := skillmeta.SetFile(path, fields...); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, f := range fields {
+		fmt.Printf("  * %s: %s = %v\n", skill.Name, f.Key, f.Value)
+	}
+}
+
+func runSkillCompare(basePath string) {
+	threshold := compare.DefaultThreshold
+	showDiff := true
+	for _, arg := range os.Args[3:] {
+		switch {
+		case arg == "--no-diff":
+			showDiff = false
+		case strings.HasPrefix(arg, "--threshold="):
+			t, err := strconv.ParseFloat(strings.TrimPrefix(arg, "--threshold="), 64)
+			if err != nil || t <= 0 || t > 1 {
+				fmt.Fprintf(os.Stderr, "error: --threshold must be a number between 0 and 1\n")
+				os.Exit(1)
+			}
+			threshold = t
+		}
+	}
+
+	groups, err := compare.FindDuplicates(loadOrgs(basePath), threshold)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(groups) == 0 {
+		fmt.Println("no duplicate skills found")
+		return
+	}
+
+	for _, g := range groups {
+		if g.Kind == "same-name" {
+			fmt.Printf("%s — same name in %d orgs\n", g.Name(), len(g.Copies))
+		} else {
+			fmt.Printf("%s — similar content\n", g.Name())
+		}
+
+		newest := g.Copies[g.Newest]
+		fmt.Printf("  ★ %s/%s  newest, modified %s\n", newest.Org, newest.Skill.Name, newest.Modified.Format("2006-01-02"))
+		for i, c := range g.Copies {
+			if i == g.Newest {
+				continue
+			}
+			fmt.Printf("    %s/%s  %.0f%% similar, modified %s\n",
+				c.Org, c.Skill.Name, g.Similarity(i)*100, c.Modified.Format("2006-01-02"))
+			for _, d := range g.FileDifferences(i) {
+				fmt.Printf("      %s\n", d)
+			}
+			if !showDiff {
+				continue
+			}
+			diff := textdiff.Unified(
+				c.Org+"/"+c.Skill.Name+"/SKILL.md",
+				newest.Org+"/"+newest.Skill.Name+"/SKILL.md",
+				c.SkillMD, newest.SkillMD)
+			for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+				fmt.Printf("      %s\n", line)
+			}
+		}
+		fmt.Println()
+	}
+}
+
+// findSkill looks a skill up by name across all orgs. "org/skill" picks one
+// org when several have a skill with the same name.
+func findSkill(orgs []config.Org, name string) (config.Skill, error) {
+	orgName := ""
+	if i := strings.Index(name, "/"); i >= 0 {
+		orgName, name = name[:i], name[i+1:]
+	}
+
+	var matches []string
+	var found config.Skill
+	for _, org := range orgs {
+		if orgName != "" && org.Name != orgName {
+			continue
+		}
+		skills, err := discovery.FindSkills(org.SkillsPath())
+		if err != nil {
+			continue
+		}
+		for _, s := range skills {
+			if s.Name == name {
+				found = s
+				matches = append(matches, org.Name+"/"+s.Name)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return config.Skill{}, fmt.Errorf("no skill named %q", name)
+	case 1:
+		return found, nil
+	default:
+		return config.Skill{}, fmt.Errorf("%q is in more than one org, use one of: %s", name, strings.Join(matches, ", "))
+	}
+}
+
+// parseField parses a key=value argument. Values starting with [ are read as
+// a YAML list, so tags=[writing, brand] sets a list; everything else is a string.
+func parseField(arg string) (skillmeta.Field, error) {
+	key, value, ok := strings.Cut(arg, "=")
+	if !ok || key == "" {
+		return skillmeta.Field{}, fmt.Errorf("expected key=value, got %q", arg)
+	}
+	if !strings.HasPrefix(value, "[") {
+		return skillmeta.Field{Key: key, Value: value}, nil
+	}
+
+	var list []string
+	if err := yaml.Unmarshal([]byte(value), &list); err != nil {
+		return skillmeta.Field{}, fmt.Errorf("can't parse %s as a list: %w", key, err)
+	}
+	return skillmeta.Field{Key: key, Value: list}, nil
+}
+
 func runPublish(basePath string) {
 	orgs := loadOrgs(basePath)
 
 	// Parse flags
 	checkOnly := false
 	writeOnly := false
-	for _, arg := range os.Args[2:] {
-		switch arg {
-		case "--check":
+	viaPR := false
+	opts := publisher.Options{SkillBumps: make(map[string]publisher.Level)}
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--check":
 			checkOnly = true
-		case "--write-only":
+		case arg == "--write-only":
 			writeOnly = true
+		case arg == "--pr":
+			viaPR = true
+		case arg == "--bump" && i+1 < len(args):
+			i++
+			addBump(&opts, args[i])
+		case strings.HasPrefix(arg, "--bump="):
+			addBump(&opts, strings.TrimPrefix(arg, "--bump="))
+		case arg == "--skill" && i+1 < len(args):
+			i++
+			opts.Skills = append(opts.Skills, args[i])
+		case strings.HasPrefix(arg, "--skill="):
+			opts.Skills = append(opts.Skills, strings.TrimPrefix(arg, "--skill="))
+		}
+	}
+
+	known := make(map[string]bool)
+	for _, org := range orgs {
+		skills, _ := discovery.FindSkills(org.SkillsPath())
+		for _, skill := range skills {
+			known[skill.Name] = true
+		}
+	}
+	for name := range opts.SkillBumps {
+		if !known[name] {
+			fmt.Fprintf(os.Stderr, "error: --bump names unknown skill %q\n", name)
+			os.Exit(1)
+		}
+	}
+	for _, name := range opts.Skills {
+		if !known[name] {
+			fmt.Fprintf(os.Stderr, "error: --skill names unknown skill %q\n", name)
+			os.Exit(1)
 		}
 	}
 
@@ -399,11 +848,30 @@ func runPublish(basePath string) {
 		}
 
 		if writeOnly {
-			runPublishWriteOnly(org, skills)
+			runPublishWriteOnly(org, skills, opts)
 			continue
 		}
 
-		runPublishFull(org, skills)
+		runPublishFull(org, skills, opts, viaPR)
+	}
+}
+
+// addBump records a --bump value: a level for every skill, or skill=level
+// for one. Exits on an unknown level.
+func addBump(opts *publisher.Options, value string) {
+	target, levelText, perSkill := strings.Cut(value, "=")
+	if !perSkill {
+		levelText = value
+	}
+	level, err := publisher.ParseLevel(levelText)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: --bump: %v\n", err)
+		os.Exit(1)
+	}
+	if perSkill {
+		opts.SkillBumps[target] = level
+	} else {
+		opts.Bump = level
 	}
 }
 
@@ -430,8 +898,13 @@ func runPublishCheck(org config.Org, skills []config.Skill) {
 	fmt.Printf("  %d of %d skills are up to date\n\n", upToDate, len(results))
 }
 
-func runPublishWriteOnly(org config.Org, skills []config.Skill) {
-	changes, err := publisher.DiffManifests(org, skills)
+func runPublishWriteOnly(org config.Org, skills []config.Skill, opts publisher.Options) {
+	plan, err := publisher.NewPlan(org, skills, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  %v\n", err)
+		return
+	}
+	changes, err := plan.Diff()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "  %v\n", err)
 		return
@@ -443,6 +916,9 @@ func runPublishWriteOnly(org config.Org, skills []config.Skill) {
 		case "new":
 			fmt.Printf("  + %s (new)\n", c.Path)
 			allUnchanged = false
+		case "removed":
+			fmt.Printf("  - %s (removed)\n", c.Path)
+			allUnchanged = false
 		case "modified":
 			fmt.Printf("  ~ %s (modified)\n", c.Path)
 			allUnchanged = false
@@ -457,7 +933,10 @@ func runPublishWriteOnly(org config.Org, skills []config.Skill) {
 		return
 	}
 
-	written, err := publisher.WriteManifests(org, skills)
+	fmt.Println()
+	printChangelog(plan.Changelog())
+
+	written, err := plan.Write()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "  %v\n", err)
 		return
@@ -465,9 +944,38 @@ func runPublishWriteOnly(org config.Org, skills []config.Skill) {
 	fmt.Printf("  wrote %d files\n\n", len(written))
 }
 
-func runPublishFull(org config.Org, skills []config.Skill) {
+// printChangelog shows the changelog entries a publish would add.
+func printChangelog(entries []publisher.ChangelogEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Println("  Changelog:")
+	for _, e := range entries {
+		fmt.Printf("    %s %s\n", e.Skill, e.Version)
+		for _, line := range e.Lines() {
+			fmt.Printf("      %s\n", line)
+		}
+	}
+	fmt.Println()
+}
+
+func runPublishFull(org config.Org, skills []config.Skill, opts publisher.Options, viaPR bool) {
+	switch org.Manifest.Publish.Mode {
+	case "", "push":
+	case "pr":
+		viaPR = true
+	default:
+		fmt.Fprintf(os.Stderr, "  unknown publish mode %q (want push or pr)\n", org.Manifest.Publish.Mode)
+		return
+	}
+
 	// Show diff preview
-	changes, err := publisher.DiffManifests(org, skills)
+	plan, err := publisher.NewPlan(org, skills, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  %v\n", err)
+		return
+	}
+	changes, err := plan.Diff()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "  %v\n", err)
 		return
@@ -481,6 +989,10 @@ func runPublishFull(org config.Org, skills []config.Skill) {
 			fmt.Printf("  + %s (new)\n", c.Path)
 			allUnchanged = false
 			changeCount++
+		case "removed":
+			fmt.Printf("  - %s (removed)\n", c.Path)
+			allUnchanged = false
+			changeCount++
 		case "modified":
 			fmt.Printf("  ~ %s (modified)\n", c.Path)
 			allUnchanged = false
@@ -490,47 +1002,99 @@ func runPublishFull(org config.Org, skills []config.Skill) {
 		}
 	}
 
-	if allUnchanged {
+	// Releases committed without tags, like ones merged from a publish PR
+	pending, err := plan.PendingTags()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  %v\n", err)
+		return
+	}
+
+	if allUnchanged && len(pending) == 0 {
 		fmt.Println("  already up to date — nothing to publish")
 		fmt.Println()
 		return
 	}
 
-	// Extract version from the first change's new content
-	version := extractVersion(changes)
+	if !runPreflight(plan, changes) {
+		return
+	}
+
+	entries := plan.Changelog()
+	releases := make([]publisher.Release, len(entries))
+	for i, e := range entries {
+		releases[i] = e.Release
+	}
+	committed := make([]publisher.Release, len(pending))
+	for i, t := range pending {
+		committed[i] = t.Release
+	}
 
 	// Get remote URL
-	brandRepoPath := filepath.Join(org.Path, org.BrandRepo)
-	remoteURL, err := publisher.RemoteURL(brandRepoPath)
+	publishPath := org.PublishPath()
+	remoteURL, err := publisher.RemoteURL(publishPath)
 	if err != nil {
 		remoteURL = "(no remote configured)"
 	}
 
 	fmt.Println()
-	fmt.Printf("  Publish marketplace v%s to %s?\n", version, remoteURL)
-	fmt.Printf("  Files to write: %d\n\n", changeCount)
+	printChangelog(entries)
+	if len(pending) > 0 {
+		fmt.Printf("  Tag already committed %s on %s?\n", releaseList(committed), remoteURL)
+	}
+	if !allUnchanged {
+		fmt.Printf("  Publish %s to %s?\n", releaseList(releases), remoteURL)
+		fmt.Printf("  Files to write: %d\n", changeCount)
+	}
+	fmt.Println()
 
-	if !confirm("  Push to GitHub?") {
+	prompt := "  Push to GitHub?"
+	switch {
+	case allUnchanged:
+		prompt = "  Push the tags to GitHub?"
+	case viaPR:
+		prompt = "  Push a publish branch and open a pull request?"
+	}
+	if !confirm(prompt) {
 		fmt.Println("  cancelled.")
 		fmt.Println()
 		return
 	}
 
-	if !confirm("  Confirm push (this will update the live marketplace):") {
+	if !viaPR && !confirm("  Confirm push (this will update the live marketplace):") {
 		fmt.Println("  cancelled.")
 		fmt.Println()
 		return
 	}
 
+	// Tag the committed releases first; in push mode the branch goes along,
+	// in case it holds a publish commit whose push failed
+	if len(pending) > 0 {
+		if err := publisher.PushTags(publishPath, pending, org.Manifest.Publish, !viaPR); err != nil {
+			fmt.Fprintf(os.Stderr, "  %v\n", err)
+			fmt.Println()
+			return
+		}
+		fmt.Printf("  tagged %s\n", releaseList(committed))
+	}
+	if allUnchanged {
+		fmt.Println()
+		return
+	}
+
 	// Write manifests
-	written, err := publisher.WriteManifests(org, skills)
+	written, err := plan.Write()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "  %v\n", err)
 		return
 	}
 
-	// Commit and push
-	err = publisher.CommitAndPush(brandRepoPath, written, version)
+	if viaPR {
+		publishPR(org, publishPath, written, releases)
+		return
+	}
+
+	// Commit, tag and push
+	err = publisher.CommitAndPush(publishPath, written, releases, org.Manifest.Publish)
 	if err != nil {
 		if errors.Is(err, publisher.ErrNoChanges) {
 			fmt.Println("  already up to date")
@@ -542,23 +1106,75 @@ func runPublishFull(org config.Org, skills []config.Skill) {
 		return
 	}
 
-	fmt.Printf("  published v%s to %s\n\n", version, remoteURL)
+	fmt.Printf("  published %s to %s\n\n", releaseList(releases), remoteURL)
 }
 
-// extractVersion parses the version from the first change's NewContent JSON.
-func extractVersion(changes []publisher.FileChange) string {
-	for _, c := range changes {
-		if c.Kind == "unchanged" {
-			continue
+// runPreflight runs the pre-publish safety checks, prints each result and
+// reports whether they all passed.
+func runPreflight(plan *publisher.Plan, changes []publisher.FileChange) bool {
+	checks, err := plan.Preflight(changes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "  %v\n", err)
+		return false
+	}
+
+	fmt.Println()
+	passed := true
+	for _, c := range checks {
+		icon := "✓"
+		if !c.OK {
+			icon = "✕"
+			passed = false
 		}
-		var pm struct {
-			Version string `json:"version"`
+		fmt.Printf("  %s %s — %s\n", icon, c.Name, c.Detail)
+	}
+	if !passed {
+		fmt.Println("  not publishing until these checks pass")
+		fmt.Println()
+	}
+	return passed
+}
+
+// publishPR commits the written files on a publish branch, pushes it and
+// prints where to open the pull request.
+func publishPR(org config.Org, publishPath string, written []publisher.WrittenFile, releases []publisher.Release) {
+	pr, err := publisher.CommitAndOpenPR(publishPath, written, releases, org.Manifest.Publish)
+	if err != nil {
+		if errors.Is(err, publisher.ErrNoChanges) {
+			fmt.Println("  already up to date")
+			fmt.Println()
+			return
 		}
-		if err := json.Unmarshal([]byte(c.NewContent), &pm); err == nil && pm.Version != "" {
-			return pm.Version
+		fmt.Fprintf(os.Stderr, "  %v\n", err)
+		fmt.Println()
+		return
+	}
+
+	fmt.Printf("  pushed %s to branch %s\n", releaseList(releases), pr.Branch)
+	switch {
+	case pr.Output != "":
+		for _, line := range strings.Split(pr.Output, "\n") {
+			fmt.Printf("  %s\n", line)
 		}
+	case pr.URL != "":
+		fmt.Printf("  open a pull request: %s\n", pr.URL)
+	default:
+		fmt.Printf("  open a pull request from %s into %s\n", pr.Branch, pr.Base)
 	}
-	return "0.1.0"
+	fmt.Println()
+}
+
+// releaseList names the release tags being published, or the marketplace
+// when no skill versions are changing.
+func releaseList(releases []publisher.Release) string {
+	if len(releases) == 0 {
+		return "marketplace"
+	}
+	tags := make([]string, len(releases))
+	for i, r := range releases {
+		tags[i] = r.Tag()
+	}
+	return strings.Join(tags, ", ")
 }
 
 // confirm prompts the user and returns true if they type "y".
@@ -577,12 +1193,27 @@ usage:
   chaparral sync       link skills to all sibling repos
   chaparral status     show link state and marketplace plugins
   chaparral validate   check skill structure for errors
+    --rules            list validation rules and their default severities
+    --fix              apply safe fixes and show a diff of each
+    --dry-run          with --fix, show the diffs without writing
   chaparral generate   generate plugin manifests (dry run to stdout)
     --marketplace      also generate marketplace.json catalog
   chaparral publish    write manifests and push marketplace to GitHub
-    --check            check if local skills are newer than published
+    --check            check if local skills changed since published
     --write-only       write manifests without pushing to GitHub
+    --pr               push a publish branch and open a pull request
+    --bump=minor       bump changed skills by major, minor or patch (default)
+    --bump=auto        infer each bump from conventional commits
+    --bump skill=major bump one skill differently; repeatable
+    --skill <name>     publish only this skill; repeatable
+  chaparral budget     estimate the tokens each repo's skills take up
   chaparral unlink     remove all managed symlinks
+  chaparral skill set <skill> key=value
+                       update SKILL.md frontmatter, keeping comments and body
+  chaparral skills compare
+                       find same-named and similar skills across orgs
+    --threshold=0.8    how similar differently-named skills must be
+    --no-diff          list duplicates without diffing them
   chaparral help       show this message`)
 }
 
@@ -596,6 +1227,8 @@ func actionIcon(action string) string {
 		return "!"
 	case "removed":
 		return "-"
+	case "updated":
+		return "*"
 	default:
 		return " "
 	}
@@ -603,14 +1236,16 @@ func actionIcon(action string) string {
 
 func stateIcon(state string) string {
 	switch state {
-	case "linked":
+	case "linked", "linked+overlay", "present", "rendered":
 		return "✓"
 	case "missing":
 		return "○"
-	case "stale":
+	case "stale", "drifted", "outdated":
 		return "◐"
-	case "conflict":
+	case "conflict", "invalid", "error":
 		return "✕"
+	case "skipped":
+		return "-"
 	default:
 		return "?"
 	}
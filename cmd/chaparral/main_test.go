@@ -0,0 +1,56 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// captureStdout runs fn and returns what it printed to stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestRunStatus_TemplatedClaudeMDWithoutTemplatesDir(t *testing.T) {
+	base := t.TempDir()
+	brand := filepath.Join(base, "test-org", "brand")
+	if err := os.MkdirAll(filepath.Join(brand, "org"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `{"org":"test-org","claude_md":"org/CLAUDE.md.tmpl","skills_dir":"skills"}`
+	if err := os.WriteFile(filepath.Join(brand, "chaparral.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(brand, "org", "CLAUDE.md.tmpl"), []byte("# {{.Repo}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(brand, "skills"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(base, "test-org", "toyon", ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() { runStatus(base) })
+
+	if !strings.Contains(out, "templates") || !strings.Contains(out, "CLAUDE.md") || !strings.Contains(out, "missing: toyon") {
+		t.Errorf("expected the templated CLAUDE.md in status, got:\n%s", out)
+	}
+}
@@ -0,0 +1,107 @@
+package budget
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+	"github.com/manzanita-research/chaparral/internal/discovery"
+	"github.com/manzanita-research/chaparral/internal/linker"
+	"github.com/manzanita-research/chaparral/internal/skillmeta"
+)
+
+// charsPerToken is the rough ratio used to estimate tokens from text size.
+const charsPerToken = 4
+
+// SkillSize is a skill's estimated token footprint. Metadata (name and
+// description) is loaded into every session; the body only when the skill
+// is used.
+type SkillSize struct {
+	Skill          string
+	MetadataTokens int
+	BodyTokens     int
+}
+
+// RepoUsage sums the skills linked into one repo. OverBudget is set when the
+// always-loaded metadata exceeds the manifest's token budget.
+type RepoUsage struct {
+	Repo           string
+	Skills         []string
+	MetadataTokens int
+	BodyTokens     int
+	OverBudget     bool
+}
+
+// Report is the token footprint of an org's skills, per skill and per repo.
+type Report struct {
+	Budget int // from the manifest; 0 means no budget
+	Skills []SkillSize
+	Repos  []RepoUsage
+}
+
+// EstimateTokens approximates how many tokens text takes, at about four
+// characters per token.
+func EstimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// SizeSkill estimates a skill's footprint from its SKILL.md.
+func SizeSkill(skill config.Skill) (SkillSize, error) {
+	path := filepath.Join(skill.Path, "SKILL.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SkillSize{}, fmt.Errorf("can't open %s: %w", path, err)
+	}
+	fm, err := skillmeta.Parse(path, data)
+	if err != nil {
+		return SkillSize{}, err
+	}
+
+	name := fm.Name
+	if name == "" {
+		name = skill.Name
+	}
+	return SkillSize{
+		Skill:          skill.Name,
+		MetadataTokens: EstimateTokens(name + ": " + fm.Description),
+		BodyTokens:     EstimateTokens(string(data[fm.BodyOffset:])),
+	}, nil
+}
+
+// ReportOrg estimates the footprint of every skill in an org and sums it for
+// each sibling repo, counting only the skills that repo links.
+func ReportOrg(org config.Org) (Report, error) {
+	skills, err := discovery.FindSkills(org.SkillsPath())
+	if err != nil {
+		return Report{}, fmt.Errorf("finding skills in %s: %w", org.Name, err)
+	}
+
+	report := Report{Budget: org.Manifest.TokenBudget}
+	sizes := make(map[string]SkillSize)
+	for _, skill := range skills {
+		size, err := SizeSkill(skill)
+		if err != nil {
+			// Unparseable skills are validate's problem; count what we can
+			continue
+		}
+		sizes[skill.Name] = size
+		report.Skills = append(report.Skills, size)
+	}
+
+	for _, repo := range org.Repos {
+		usage := RepoUsage{Repo: repo}
+		for _, choice := range linker.SelectSkills(org, repo, skills) {
+			size, ok := sizes[choice.Skill.Name]
+			if !choice.Selected || !ok {
+				continue
+			}
+			usage.Skills = append(usage.Skills, choice.Skill.Name)
+			usage.MetadataTokens += size.MetadataTokens
+			usage.BodyTokens += size.BodyTokens
+		}
+		usage.OverBudget = report.Budget > 0 && usage.MetadataTokens > report.Budget
+		report.Repos = append(report.Repos, usage)
+	}
+	return report, nil
+}
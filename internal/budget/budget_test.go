@@ -0,0 +1,91 @@
+package budget
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+func makeOrg(t *testing.T, skills map[string]string, repos ...string) config.Org {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range skills {
+		skillDir := filepath.Join(dir, "brand", "skills", name)
+		if err := os.MkdirAll(skillDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, repo := range repos {
+		if err := os.MkdirAll(filepath.Join(dir, repo), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return config.Org{
+		Name:      "test-org",
+		Path:      dir,
+		BrandRepo: "brand",
+		Manifest:  config.Manifest{Org: "test-org", SkillsDir: "skills"},
+		Repos:     repos,
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	tests := map[string]int{"": 0, "abc": 1, "abcd": 1, "abcde": 2}
+	for text, want := range tests {
+		if got := EstimateTokens(text); got != want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestSizeSkill(t *testing.T) {
+	org := makeOrg(t, map[string]string{
+		// "voice: " + 13-char description = 20 chars = 5 tokens; 40-char body = 10 tokens
+		"voice": "---\nname: voice\ndescription: Write warmly.\n---\n" + strings.Repeat("x", 39) + "\n",
+	})
+
+	size, err := SizeSkill(config.Skill{Name: "voice", Path: filepath.Join(org.SkillsPath(), "voice")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if size.MetadataTokens != 5 || size.BodyTokens != 10 {
+		t.Errorf("size = %+v, want 5 metadata and 10 body tokens", size)
+	}
+}
+
+func TestReportOrg(t *testing.T) {
+	long := strings.Repeat("word ", 40)
+	org := makeOrg(t, map[string]string{
+		"voice":  "---\nname: voice\ndescription: " + long + "\n---\n",
+		"review": "---\nname: review\ndescription: " + long + "\n---\n",
+	}, "toyon", "manzanita")
+	org.Manifest.Repos = map[string]config.RepoConfig{"toyon": {Skills: []string{"voice"}}}
+	org.Manifest.TokenBudget = 80
+
+	report, err := ReportOrg(org)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Skills) != 2 || len(report.Repos) != 2 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	for _, usage := range report.Repos {
+		switch usage.Repo {
+		case "toyon":
+			if len(usage.Skills) != 1 || usage.OverBudget {
+				t.Errorf("toyon = %+v, want one skill within budget", usage)
+			}
+		case "manzanita":
+			if len(usage.Skills) != 2 || !usage.OverBudget {
+				t.Errorf("manzanita = %+v, want two skills over budget", usage)
+			}
+		}
+	}
+}
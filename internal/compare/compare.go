@@ -0,0 +1,191 @@
+package compare
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+	"github.com/manzanita-research/chaparral/internal/discovery"
+	"github.com/manzanita-research/chaparral/internal/similarity"
+)
+
+// DefaultThreshold is how similar two differently-named skills' SKILL.md
+// files must be to be reported together.
+const DefaultThreshold = 0.8
+
+// Copy is one org's version of a skill.
+type Copy struct {
+	Org      string
+	Skill    config.Skill
+	Modified time.Time // latest modification time of any file in the skill
+	SkillMD  string
+	files    map[string][]byte // every file in the skill, by relative path
+}
+
+// Group is a set of copies that look like the same skill: they share a name
+// ("same-name") or their SKILL.md content is close ("similar"). Newest is the
+// index of the most recently modified copy.
+type Group struct {
+	Kind   string
+	Copies []Copy
+	Newest int
+}
+
+// Name describes the group: the shared name, or the names joined with " ~ ".
+func (g Group) Name() string {
+	if g.Kind == "same-name" {
+		return g.Copies[0].Skill.Name
+	}
+	name := g.Copies[0].Skill.Name
+	for _, c := range g.Copies[1:] {
+		name += " ~ " + c.Skill.Name
+	}
+	return name
+}
+
+// Similarity scores a copy's SKILL.md against the group's newest copy.
+func (g Group) Similarity(i int) float64 {
+	return similarity.Cosine(g.Copies[g.Newest].SkillMD, g.Copies[i].SkillMD)
+}
+
+// FileDifferences lists files that differ between a copy and the newest one,
+// other than SKILL.md: "only in <org>: path" or "differs: path".
+func (g Group) FileDifferences(i int) []string {
+	newest, other := g.Copies[g.Newest], g.Copies[i]
+	var diffs []string
+	for _, path := range sortedPaths(newest.files, other.files) {
+		if path == "SKILL.md" {
+			continue
+		}
+		a, inNewest := newest.files[path]
+		b, inOther := other.files[path]
+		switch {
+		case !inOther:
+			diffs = append(diffs, fmt.Sprintf("only in %s: %s", newest.Org, path))
+		case !inNewest:
+			diffs = append(diffs, fmt.Sprintf("only in %s: %s", other.Org, path))
+		case !bytes.Equal(a, b):
+			diffs = append(diffs, "differs: "+path)
+		}
+	}
+	return diffs
+}
+
+// FindDuplicates looks across orgs for skills with the same name, and for
+// differently-named skills in different orgs whose SKILL.md similarity is at
+// least threshold. Groups are sorted by name.
+func FindDuplicates(orgs []config.Org, threshold float64) ([]Group, error) {
+	var copies []Copy
+	for _, org := range orgs {
+		skills, err := discovery.FindSkills(org.SkillsPath())
+		if err != nil {
+			continue
+		}
+		for _, skill := range skills {
+			c, err := loadCopy(org.Name, skill)
+			if err != nil {
+				return nil, err
+			}
+			copies = append(copies, c)
+		}
+	}
+
+	var groups []Group
+	byName := make(map[string][]Copy)
+	var names []string
+	for _, c := range copies {
+		if _, seen := byName[c.Skill.Name]; !seen {
+			names = append(names, c.Skill.Name)
+		}
+		byName[c.Skill.Name] = append(byName[c.Skill.Name], c)
+	}
+	for _, name := range names {
+		if len(byName[name]) > 1 {
+			groups = append(groups, newGroup("same-name", byName[name]))
+		}
+	}
+
+	for i := 0; i < len(copies); i++ {
+		for j := i + 1; j < len(copies); j++ {
+			a, b := copies[i], copies[j]
+			if a.Org == b.Org || a.Skill.Name == b.Skill.Name {
+				continue
+			}
+			if similarity.Cosine(a.SkillMD, b.SkillMD) >= threshold {
+				groups = append(groups, newGroup("similar", []Copy{a, b}))
+			}
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Name() < groups[j].Name() })
+	return groups, nil
+}
+
+func newGroup(kind string, copies []Copy) Group {
+	g := Group{Kind: kind, Copies: copies}
+	for i, c := range copies {
+		if c.Modified.After(copies[g.Newest].Modified) {
+			g.Newest = i
+		}
+	}
+	return g
+}
+
+// loadCopy reads every file in a skill directory, skipping dotfiles.
+func loadCopy(org string, skill config.Skill) (Copy, error) {
+	c := Copy{Org: org, Skill: skill, files: make(map[string][]byte)}
+	err := filepath.WalkDir(skill.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != skill.Path && d.Name()[0] == '.' {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(c.Modified) {
+			c.Modified = info.ModTime()
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(skill.Path, path)
+		c.files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		return Copy{}, fmt.Errorf("reading %s/%s: %w", org, skill.Name, err)
+	}
+	c.SkillMD = string(c.files["SKILL.md"])
+	return c, nil
+}
+
+func sortedPaths(a, b map[string][]byte) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, m := range []map[string][]byte{a, b} {
+		for p := range m {
+			if !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,112 @@
+package compare
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// makeOrg creates an org under base with the given skills, each mapping a
+// relative path to file content.
+func makeOrg(t *testing.T, base, name string, skills map[string]map[string]string) config.Org {
+	t.Helper()
+	orgPath := filepath.Join(base, name)
+	for skill, files := range skills {
+		for rel, content := range files {
+			path := filepath.Join(orgPath, "brand", "skills", skill, rel)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return config.Org{
+		Name:      name,
+		Path:      orgPath,
+		BrandRepo: "brand",
+		Manifest:  config.Manifest{Org: name, SkillsDir: "skills"},
+	}
+}
+
+func touch(t *testing.T, path string, when time.Time) {
+	t.Helper()
+	if err := os.Chtimes(path, when, when); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindDuplicates_SameName(t *testing.T) {
+	base := t.TempDir()
+	voice := "---\nname: brand-voice\ndescription: Write in our voice\n---\nBe warm and direct.\n"
+	a := makeOrg(t, base, "manzanita", map[string]map[string]string{
+		"brand-voice": {"SKILL.md": voice, "examples.md": "one\n"},
+	})
+	b := makeOrg(t, base, "acme", map[string]map[string]string{
+		"brand-voice": {"SKILL.md": voice + "Avoid jargon.\n", "glossary.md": "terms\n"},
+		"go-review":   {"SKILL.md": "---\nname: go-review\n---\nReview Go code.\n"},
+	})
+
+	old := time.Now().Add(-48 * time.Hour)
+	for _, f := range []string{"SKILL.md", "examples.md"} {
+		touch(t, filepath.Join(a.SkillsPath(), "brand-voice", f), old)
+	}
+
+	groups, err := FindDuplicates([]config.Org{a, b}, DefaultThreshold)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+
+	g := groups[0]
+	if g.Kind != "same-name" || g.Name() != "brand-voice" || len(g.Copies) != 2 {
+		t.Fatalf("unexpected group %+v", g)
+	}
+	if g.Copies[g.Newest].Org != "acme" {
+		t.Errorf("newest = %s, want acme", g.Copies[g.Newest].Org)
+	}
+
+	older := 1 - g.Newest
+	diffs := g.FileDifferences(older)
+	want := []string{"only in manzanita: examples.md", "only in acme: glossary.md"}
+	if len(diffs) != 2 {
+		t.Fatalf("FileDifferences = %v, want %v", diffs, want)
+	}
+	for _, w := range want {
+		found := false
+		for _, d := range diffs {
+			found = found || d == w
+		}
+		if !found {
+			t.Errorf("missing %q in %v", w, diffs)
+		}
+	}
+}
+
+func TestFindDuplicates_Similar(t *testing.T) {
+	base := t.TempDir()
+	a := makeOrg(t, base, "manzanita", map[string]map[string]string{
+		"code-review": {"SKILL.md": "---\nname: code-review\ndescription: Review code for bugs\n---\nCheck error handling, naming and tests.\n"},
+	})
+	b := makeOrg(t, base, "acme", map[string]map[string]string{
+		"review-code":   {"SKILL.md": "---\nname: review-code\ndescription: Review code for bugs\n---\nCheck error handling, naming and tests.\n"},
+		"release-notes": {"SKILL.md": "---\nname: release-notes\ndescription: Write release notes\n---\nSummarise merged changes.\n"},
+	})
+
+	groups, err := FindDuplicates([]config.Org{a, b}, DefaultThreshold)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 1 || groups[0].Kind != "similar" {
+		t.Fatalf("expected one similar group, got %+v", groups)
+	}
+	if s := groups[0].Similarity(1 - groups[0].Newest); s < DefaultThreshold {
+		t.Errorf("similarity = %.2f, want at least %.2f", s, DefaultThreshold)
+	}
+}
@@ -0,0 +1,226 @@
+package condition
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// skipDirs are never descended into when matching ** globs.
+var skipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// Validate checks that a condition sets exactly one test, recursively.
+func Validate(c config.Condition) error {
+	set := 0
+	if c.Exists != "" {
+		set++
+	}
+	if c.Glob != "" {
+		set++
+		if _, err := path.Match(strings.ReplaceAll(c.Glob, "**", "*"), ""); err != nil {
+			return fmt.Errorf("bad glob %q: %w", c.Glob, err)
+		}
+	}
+	if c.JSON != nil {
+		set++
+		if c.JSON.File == "" || c.JSON.Path == "" {
+			return fmt.Errorf("json condition needs both file and path")
+		}
+	}
+	if c.All != nil {
+		set++
+	}
+	if c.Any != nil {
+		set++
+	}
+	if c.Not != nil {
+		set++
+		if err := Validate(*c.Not); err != nil {
+			return err
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("condition must set exactly one of exists, glob, json, all, any, not")
+	}
+
+	for _, sub := range c.All {
+		if err := Validate(sub); err != nil {
+			return err
+		}
+	}
+	for _, sub := range c.Any {
+		if err := Validate(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Evaluate tests a condition against a repo. The explanation says what was
+// found or missing, for display next to a skipped skill.
+func Evaluate(c config.Condition, repoPath string) (bool, string) {
+	if err := Validate(c); err != nil {
+		return false, "invalid condition: " + err.Error()
+	}
+
+	switch {
+	case c.Exists != "":
+		if _, err := os.Stat(filepath.Join(repoPath, c.Exists)); err != nil {
+			return false, c.Exists + " not found"
+		}
+		return true, c.Exists + " exists"
+
+	case c.Glob != "":
+		match, err := matchGlob(repoPath, c.Glob)
+		if err != nil {
+			return false, err.Error()
+		}
+		if match == "" {
+			return false, "nothing matches " + c.Glob
+		}
+		return true, match + " matches " + c.Glob
+
+	case c.JSON != nil:
+		return evaluateJSON(*c.JSON, repoPath)
+
+	case c.All != nil:
+		var reasons []string
+		for _, sub := range c.All {
+			ok, reason := Evaluate(sub, repoPath)
+			if !ok {
+				return false, reason
+			}
+			reasons = append(reasons, reason)
+		}
+		return true, strings.Join(reasons, ", ")
+
+	case c.Any != nil:
+		var reasons []string
+		for _, sub := range c.Any {
+			ok, reason := Evaluate(sub, repoPath)
+			if ok {
+				return true, reason
+			}
+			reasons = append(reasons, reason)
+		}
+		return false, strings.Join(reasons, ", ")
+
+	default: // c.Not != nil
+		ok, reason := Evaluate(*c.Not, repoPath)
+		return !ok, "not (" + reason + ")"
+	}
+}
+
+// evaluateJSON looks up a dot-separated path in a JSON file. With Equals set,
+// the value must also match it (numbers and booleans compare by their JSON text).
+func evaluateJSON(c config.JSONCondition, repoPath string) (bool, string) {
+	data, err := os.ReadFile(filepath.Join(repoPath, c.File))
+	if err != nil {
+		return false, c.File + " not found"
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return false, fmt.Sprintf("can't parse %s: %v", c.File, err)
+	}
+
+	value := doc
+	for _, key := range strings.Split(c.Path, ".") {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return false, fmt.Sprintf("%s has no %s", c.File, c.Path)
+		}
+		if value, ok = obj[key]; !ok {
+			return false, fmt.Sprintf("%s has no %s", c.File, c.Path)
+		}
+	}
+
+	if c.Equals == "" {
+		return true, fmt.Sprintf("%s has %s", c.File, c.Path)
+	}
+
+	got, ok := value.(string)
+	if !ok {
+		encoded, _ := json.Marshal(value)
+		got = string(encoded)
+	}
+	if got != c.Equals {
+		return false, fmt.Sprintf("%s %s is %q, not %q", c.File, c.Path, got, c.Equals)
+	}
+	return true, fmt.Sprintf("%s %s is %q", c.File, c.Path, got)
+}
+
+// matchGlob returns the first repo-relative path matching pattern, or "".
+// Patterns without ** use filepath.Glob; ** walks the repo.
+func matchGlob(repoPath, pattern string) (string, error) {
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(filepath.Join(repoPath, pattern))
+		if err != nil {
+			return "", fmt.Errorf("bad glob %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return "", nil
+		}
+		rel, _ := filepath.Rel(repoPath, matches[0])
+		return filepath.ToSlash(rel), nil
+	}
+
+	patternParts := strings.Split(pattern, "/")
+	var found string
+	err := filepath.WalkDir(repoPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && skipDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(repoPath, p)
+		if err != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if matchParts(patternParts, strings.Split(rel, "/")) {
+			found = rel
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return found, nil
+}
+
+// matchParts matches path segments against pattern segments, where a "**"
+// segment matches zero or more path segments.
+func matchParts(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchParts(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	ok, err := path.Match(pattern[0], parts[0])
+	if err != nil || !ok {
+		return false
+	}
+	return matchParts(pattern[1:], parts[1:])
+}
@@ -0,0 +1,80 @@
+package condition
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// setupRepo creates a repo with a go.mod, a nested terraform file, and a
+// package.json that depends on react.
+func setupRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":             "module example\n",
+		"infra/prod/main.tf": "",
+		"package.json":       `{"name": "web", "private": true, "dependencies": {"react": "^18.2.0"}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestEvaluate(t *testing.T) {
+	repo := setupRepo(t)
+
+	tests := []struct {
+		name string
+		cond config.Condition
+		want bool
+	}{
+		{"exists", config.Condition{Exists: "go.mod"}, true},
+		{"exists missing", config.Condition{Exists: "Cargo.toml"}, false},
+		{"glob", config.Condition{Glob: "*.mod"}, true},
+		{"glob no match", config.Condition{Glob: "*.rs"}, false},
+		{"glob any depth", config.Condition{Glob: "**/*.tf"}, true},
+		{"glob any depth no match", config.Condition{Glob: "**/*.py"}, false},
+		{"json path", config.Condition{JSON: &config.JSONCondition{File: "package.json", Path: "dependencies.react"}}, true},
+		{"json path missing", config.Condition{JSON: &config.JSONCondition{File: "package.json", Path: "dependencies.vue"}}, false},
+		{"json equals", config.Condition{JSON: &config.JSONCondition{File: "package.json", Path: "name", Equals: "web"}}, true},
+		{"json equals bool", config.Condition{JSON: &config.JSONCondition{File: "package.json", Path: "private", Equals: "true"}}, true},
+		{"json equals mismatch", config.Condition{JSON: &config.JSONCondition{File: "package.json", Path: "name", Equals: "api"}}, false},
+		{"all", config.Condition{All: []config.Condition{{Exists: "go.mod"}, {Exists: "package.json"}}}, true},
+		{"all fails", config.Condition{All: []config.Condition{{Exists: "go.mod"}, {Exists: "Cargo.toml"}}}, false},
+		{"any", config.Condition{Any: []config.Condition{{Exists: "Cargo.toml"}, {Exists: "go.mod"}}}, true},
+		{"not", config.Condition{Not: &config.Condition{Exists: "Cargo.toml"}}, true},
+		{"empty is invalid", config.Condition{}, false},
+		{"two tests is invalid", config.Condition{Exists: "go.mod", Glob: "*.mod"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := Evaluate(tt.cond, repo)
+			if got != tt.want {
+				t.Errorf("Evaluate = %v (%s), want %v", got, reason, tt.want)
+			}
+			if reason == "" {
+				t.Error("expected an explanation")
+			}
+		})
+	}
+}
+
+func TestEvaluate_Explanation(t *testing.T) {
+	repo := setupRepo(t)
+
+	_, reason := Evaluate(config.Condition{Exists: "Cargo.toml"}, repo)
+	if reason != "Cargo.toml not found" {
+		t.Errorf("reason = %q, want %q", reason, "Cargo.toml not found")
+	}
+}
@@ -9,17 +9,92 @@ import (
 
 // Manifest represents a chaparral.json file in a brand repo.
 type Manifest struct {
-	Org       string   `json:"org"`
-	ClaudeMD  string   `json:"claude_md"`
-	SkillsDir string   `json:"skills_dir"`
-	Exclude   []string `json:"exclude"`
+	Org          string                 `json:"org"`
+	ClaudeMD     string                 `json:"claude_md"`
+	SkillsDir    string                 `json:"skills_dir"`
+	TemplatesDir string                 `json:"templates_dir"`
+	Exclude      []string               `json:"exclude"`
+	Vars         map[string]string      `json:"vars"`
+	Repos        map[string]RepoConfig  `json:"repos"`
+	Skills       map[string]SkillConfig `json:"skills"`
+	MCP          MCPConfig              `json:"mcp"`
+	Validation   ValidationConfig       `json:"validation"`
+	TokenBudget  int                    `json:"token_budget"` // max estimated tokens of skill metadata per repo; 0 means none
+	Publish      PublishConfig          `json:"publish"`
+}
+
+// RepoConfig holds per-repo settings, keyed by repo name in the manifest.
+type RepoConfig struct {
+	Skills []string          `json:"skills"` // skills to link; nil means all of them
+	Vars   map[string]string `json:"vars"`
+}
+
+// SkillConfig holds per-skill settings, keyed by skill name in the manifest.
+type SkillConfig struct {
+	When       *Condition `json:"when"`       // link only into repos where this holds
+	Visibility string     `json:"visibility"` // "internal" keeps the skill out of the marketplace
+}
+
+// Condition is a test against a repo's contents. Set exactly one field;
+// All, Any and Not combine nested conditions.
+type Condition struct {
+	Exists string         `json:"exists,omitempty"` // path relative to the repo root
+	Glob   string         `json:"glob,omitempty"`   // pattern relative to the repo root; ** matches any depth
+	JSON   *JSONCondition `json:"json,omitempty"`
+	All    []Condition    `json:"all,omitempty"`
+	Any    []Condition    `json:"any,omitempty"`
+	Not    *Condition     `json:"not,omitempty"`
+}
+
+// JSONCondition matches a value inside a JSON file such as package.json.
+type JSONCondition struct {
+	File   string `json:"file"`             // relative to the repo root
+	Path   string `json:"path"`             // dot-separated, e.g. "dependencies.react"
+	Equals string `json:"equals,omitempty"` // if empty, the path only has to exist
+}
+
+// ValidationConfig tunes how strictly an org's skills are validated.
+type ValidationConfig struct {
+	Rules            map[string]string `json:"rules"`             // rule ID → "error", "warning" or "off"
+	RequiredFields   []string          `json:"required_fields"`   // frontmatter keys every skill must set
+	BannedWords      []string          `json:"banned_words"`      // words and phrases SKILL.md must not use, any case
+	DefaultLicense   string            `json:"default_license"`   // license validate --fix adds to skills without one
+	ClaudeMDBudget   int               `json:"claude_md_budget"`  // max bytes for the org CLAUDE.md; 0 means the default
+	IgnoreOverlap    [][]string        `json:"ignore_overlap"`    // skill pairs whose similar descriptions are intended
+	OverlapThreshold float64           `json:"overlap_threshold"` // description similarity (0-1) that counts as overlap; 0 means the default
+}
+
+// PublishConfig controls where and what chaparral publish publishes.
+type PublishConfig struct {
+	Target    string   `json:"target"`     // repo to publish into, relative to the org directory or absolute; empty means the brand repo
+	Skills    []string `json:"skills"`     // skills to publish; nil means all of them
+	Mode      string   `json:"mode"`       // "push" (the default) or "pr" to publish through a pull request
+	PRCommand string   `json:"pr_command"` // shell command run after the PR branch is pushed, e.g. gh pr create
+	Author    string   `json:"author"`     // publish commit author as "Name <email>"; empty uses the git config identity
+	Message   string   `json:"message"`    // text/template for the publish commit message; empty names the releases
+	Sign      bool     `json:"sign"`       // sign the publish commit and tags with the signing key in git config
+}
+
+// MCPConfig lists the MCP servers chaparral manages in each sibling's .mcp.json.
+type MCPConfig struct {
+	Servers map[string]MCPServer `json:"servers"`
+}
+
+// MCPServer is a single entry in the .mcp.json "mcpServers" map.
+type MCPServer struct {
+	Type    string            `json:"type,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
 }
 
 // Org represents a discovered organization directory.
 type Org struct {
 	Name      string
-	Path      string   // absolute path to org directory
-	BrandRepo string   // name of the brand repo within the org
+	Path      string // absolute path to org directory
+	BrandRepo string // name of the brand repo within the org
 	Manifest  Manifest
 	Repos     []string // sibling repo names (excluding brand and excluded)
 }
@@ -50,11 +125,52 @@ func (o *Org) SkillsPath() string {
 	return filepath.Join(o.Path, o.BrandRepo, o.Manifest.SkillsDir)
 }
 
+// PublishPath returns the absolute path to the repo skills are published to:
+// the manifest's publish target, or the brand repo.
+func (o *Org) PublishPath() string {
+	target := o.Manifest.Publish.Target
+	if target == "" {
+		return filepath.Join(o.Path, o.BrandRepo)
+	}
+	if filepath.IsAbs(target) {
+		return filepath.Clean(target)
+	}
+	return filepath.Join(o.Path, target)
+}
+
+// PublishesSeparately reports whether the org publishes to a repo other than
+// its brand repo.
+func (o *Org) PublishesSeparately() bool {
+	return o.PublishPath() != filepath.Join(o.Path, o.BrandRepo)
+}
+
 // ClaudeMDPath returns the absolute path to the org-level CLAUDE.md.
 func (o *Org) ClaudeMDPath() string {
 	return filepath.Join(o.Path, o.BrandRepo, o.Manifest.ClaudeMD)
 }
 
+// TemplatesPath returns the absolute path to the templates directory,
+// or "" if the manifest doesn't configure one.
+func (o *Org) TemplatesPath() string {
+	if o.Manifest.TemplatesDir == "" {
+		return ""
+	}
+	return filepath.Join(o.Path, o.BrandRepo, o.Manifest.TemplatesDir)
+}
+
+// RepoVars returns the template variables for a repo: org-wide vars from the
+// manifest, overridden by that repo's own vars.
+func (o *Org) RepoVars(repo string) map[string]string {
+	vars := make(map[string]string)
+	for k, v := range o.Manifest.Vars {
+		vars[k] = v
+	}
+	for k, v := range o.Manifest.Repos[repo].Vars {
+		vars[k] = v
+	}
+	return vars
+}
+
 // IsExcluded checks if a repo name should be excluded from linking.
 func (o *Org) IsExcluded(repo string) bool {
 	for _, ex := range o.Manifest.Exclude {
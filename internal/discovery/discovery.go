@@ -55,7 +55,12 @@ func scanOrgDir(orgPath string) (config.Org, bool, error) {
 			continue
 		}
 
-		repos := discoverRepos(orgPath, entries, entry.Name(), manifest.Exclude)
+		exclude := manifest.Exclude
+		if target := manifest.Publish.Target; target != "" && !filepath.IsAbs(target) {
+			// A sibling marketplace repo isn't a project to link skills into
+			exclude = append(exclude, filepath.Clean(target))
+		}
+		repos := discoverRepos(orgPath, entries, entry.Name(), exclude)
 
 		org := config.Org{
 			Name:      manifest.Org,
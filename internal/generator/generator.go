@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/manzanita-research/chaparral/internal/config"
 	"github.com/manzanita-research/chaparral/internal/skillmeta"
@@ -13,11 +14,13 @@ const initialVersion = "0.1.0"
 
 // PluginManifest is the Claude Code plugin.json format for a single skill.
 type PluginManifest struct {
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	Version     string `json:"version"`
-	License     string `json:"license,omitempty"`
-	Skills      string `json:"skills,omitempty"`
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	Version      string `json:"version"`
+	License      string `json:"license,omitempty"`
+	Skills       string `json:"skills,omitempty"`
+	ContentHash  string `json:"contentHash,omitempty"`  // hash of the skill's files when this version was published
+	SourceCommit string `json:"sourceCommit,omitempty"` // brand repo commit the skill was copied from, with a publish target
 }
 
 // MarketplaceManifest is the Claude Code marketplace.json format.
@@ -66,7 +69,30 @@ func GeneratePluginJSON(skill config.Skill) ([]byte, error) {
 	return json.MarshalIndent(manifest, "", "  ")
 }
 
+// IsInternal reports whether a skill must stay out of the marketplace: its
+// frontmatter sets publish: false or visibility: internal, or the manifest
+// sets its visibility to "internal".
+func IsInternal(org config.Org, skill config.Skill, fm skillmeta.Frontmatter) bool {
+	if publish, ok := fm.Fields["publish"].(bool); ok && !publish {
+		return true
+	}
+	if v, ok := fm.Fields["visibility"].(string); ok && strings.EqualFold(strings.TrimSpace(v), "internal") {
+		return true
+	}
+	return strings.EqualFold(org.Manifest.Skills[skill.Name].Visibility, "internal")
+}
+
+// SkillIsInternal is IsInternal for a skill whose SKILL.md hasn't been read yet.
+func SkillIsInternal(org config.Org, skill config.Skill) (bool, error) {
+	fm, err := skillmeta.ParseFrontmatter(filepath.Join(skill.Path, "SKILL.md"))
+	if err != nil {
+		return false, fmt.Errorf("reading %s: %w", skill.Name, err)
+	}
+	return IsInternal(org, skill, fm), nil
+}
+
 // GenerateMarketplace creates a MarketplaceManifest from an org and its skills.
+// Internal skills are left out.
 func GenerateMarketplace(org config.Org, skills []config.Skill) (MarketplaceManifest, error) {
 	var plugins []MarketplacePlugin
 
@@ -76,6 +102,9 @@ func GenerateMarketplace(org config.Org, skills []config.Skill) (MarketplaceMani
 		if err != nil {
 			return MarketplaceManifest{}, fmt.Errorf("reading %s: %w", skill.Name, err)
 		}
+		if IsInternal(org, skill, fm) {
+			continue
+		}
 
 		source := "./" + filepath.ToSlash(filepath.Join(org.Manifest.SkillsDir, skill.Name))
 
@@ -143,6 +143,31 @@ func TestGenerateMarketplace_MultipleSkills(t *testing.T) {
 	}
 }
 
+func TestGenerateMarketplace_SkipsInternal(t *testing.T) {
+	dir := t.TempDir()
+	skills := []config.Skill{
+		makeSkill(t, dir, "brand-voice", "---\nname: brand-voice\ndescription: Voice\n---\n"),
+		makeSkill(t, dir, "client-notes", "---\nname: client-notes\ndescription: Notes\npublish: false\n---\n"),
+		makeSkill(t, dir, "pricing", "---\nname: pricing\ndescription: Pricing\nvisibility: internal\n---\n"),
+		makeSkill(t, dir, "playbook", "---\nname: playbook\ndescription: Playbook\n---\n"),
+	}
+	org := config.Org{
+		Name: "manzanita-research",
+		Manifest: config.Manifest{
+			Org:    "manzanita-research",
+			Skills: map[string]config.SkillConfig{"playbook": {Visibility: "internal"}},
+		},
+	}
+
+	marketplace, err := GenerateMarketplace(org, skills)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(marketplace.Plugins) != 1 || marketplace.Plugins[0].Name != "brand-voice" {
+		t.Errorf("expected only brand-voice, got %+v", marketplace.Plugins)
+	}
+}
+
 func TestGenerateMarketplace_NameFromOrg(t *testing.T) {
 	dir := t.TempDir()
 	skills := []config.Skill{
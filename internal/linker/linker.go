@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/manzanita-research/chaparral/internal/config"
 	"github.com/manzanita-research/chaparral/internal/discovery"
+	"github.com/manzanita-research/chaparral/internal/mcp"
+	"github.com/manzanita-research/chaparral/internal/render"
 )
 
 // LinkResult describes what happened for a single link operation.
@@ -17,7 +20,8 @@ type LinkResult struct {
 	Detail  string
 }
 
-// SyncOrg links all skills and the org CLAUDE.md for a given org.
+// SyncOrg links all skills and the org CLAUDE.md for a given org, renders
+// templates into each sibling repo, and merges managed MCP servers.
 func SyncOrg(org config.Org) ([]LinkResult, error) {
 	var results []LinkResult
 
@@ -31,14 +35,38 @@ func SyncOrg(org config.Org) ([]LinkResult, error) {
 		return results, fmt.Errorf("finding skills: %w", err)
 	}
 
-	// Link skills to each sibling repo
+	// Link selected skills to each sibling repo, removing ones no longer selected
 	for _, repo := range org.Repos {
-		for _, skill := range skills {
-			result := linkSkill(org, repo, skill)
+		for _, choice := range SelectSkills(org, repo, skills) {
+			if !choice.Selected {
+				if result, ok := unlinkSkill(org, repo, choice.Skill); ok {
+					result.Detail = choice.Reason
+					results = append(results, result)
+				}
+				continue
+			}
+			result := linkSkill(org, repo, choice.Skill)
+			if result.Detail == "" {
+				result.Detail = choice.Reason
+			}
 			results = append(results, result)
 		}
 	}
 
+	// Render templates, and a templated org CLAUDE.md, into each sibling repo
+	templates, err := render.OrgTemplates(org)
+	if err != nil {
+		return results, err
+	}
+	for _, repo := range org.Repos {
+		for _, r := range render.SyncRepo(org, repo, templates) {
+			results = append(results, LinkResult{Repo: r.Repo, Skill: r.Path, Action: r.Action, Detail: r.Detail})
+		}
+	}
+
+	// Merge managed MCP servers into each sibling's .mcp.json
+	results = append(results, syncMCP(org)...)
+
 	return results, nil
 }
 
@@ -63,16 +91,23 @@ func UnlinkOrg(org config.Org) ([]LinkResult, error) {
 
 	for _, repo := range org.Repos {
 		for _, skill := range skills {
-			linkPath := filepath.Join(org.Path, repo, ".claude", "skills", skill.Name)
-			if isOurSymlink(linkPath) {
-				os.Remove(linkPath)
-				results = append(results, LinkResult{
-					Repo: repo, Skill: skill.Name, Action: "removed",
-				})
+			if result, ok := unlinkSkill(org, repo, skill); ok {
+				results = append(results, result)
 			}
 		}
 	}
 
+	// Remove rendered template output
+	templates, err := render.OrgTemplates(org)
+	if err != nil {
+		return results, err
+	}
+	for _, repo := range org.Repos {
+		for _, r := range render.RemoveRepo(org, repo, templates) {
+			results = append(results, LinkResult{Repo: r.Repo, Skill: r.Path, Action: r.Action})
+		}
+	}
+
 	return results, nil
 }
 
@@ -80,8 +115,14 @@ func UnlinkOrg(org config.Org) ([]LinkResult, error) {
 type LinkStatus struct {
 	Repo      string
 	Skill     string
-	State     string // "linked", "stale", "missing", "conflict"
+	State     string // "linked", "linked+overlay", "stale", "missing", "conflict", "skipped"
 	LinkTarget string
+	Detail    string // why a skill was skipped or pulled in
+}
+
+// IsLinked returns true if the skill is live in the repo, with or without an overlay.
+func (s LinkStatus) IsLinked() bool {
+	return s.State == "linked" || s.State == "linked+overlay"
 }
 
 func StatusOrg(org config.Org) ([]LinkStatus, error) {
@@ -90,7 +131,12 @@ func StatusOrg(org config.Org) ([]LinkStatus, error) {
 	// Check org CLAUDE.md
 	claudeDest := filepath.Join(org.Path, "CLAUDE.md")
 	claudeSource := org.ClaudeMDPath()
-	statuses = append(statuses, checkLink(claudeDest, claudeSource, "(org)", "CLAUDE.md"))
+	if render.IsTemplate(org.Manifest.ClaudeMD) {
+		// Reported with the rendered templates
+		statuses = append(statuses, LinkStatus{Repo: "(org)", Skill: "CLAUDE.md", State: "skipped", Detail: "rendered into each repo"})
+	} else {
+		statuses = append(statuses, checkLink(claudeDest, claudeSource, "(org)", "CLAUDE.md"))
+	}
 
 	// Check skills
 	skills, err := discovery.FindSkills(org.SkillsPath())
@@ -99,19 +145,66 @@ func StatusOrg(org config.Org) ([]LinkStatus, error) {
 	}
 
 	for _, repo := range org.Repos {
-		for _, skill := range skills {
+		for _, choice := range SelectSkills(org, repo, skills) {
+			skill := choice.Skill
 			linkPath := filepath.Join(org.Path, repo, ".claude", "skills", skill.Name)
-			statuses = append(statuses, checkLink(linkPath, skill.Path, repo, skill.Name))
+			if !choice.Selected {
+				st := LinkStatus{Repo: repo, Skill: skill.Name, State: "skipped", Detail: choice.Reason}
+				if isOurSymlink(linkPath) || isOverlayDir(linkPath) {
+					// Still linked from before — sync will remove it
+					st.State = "stale"
+				}
+				statuses = append(statuses, st)
+				continue
+			}
+			if overlay, ok := materialized(org, repo, skill); ok {
+				statuses = append(statuses, checkOverlay(org, skill, overlay, linkPath, repo))
+				continue
+			}
+			if isOverlayDir(linkPath) {
+				// Overlay or templates were removed — sync will restore the plain link
+				statuses = append(statuses, LinkStatus{Repo: repo, Skill: skill.Name, State: "stale"})
+				continue
+			}
+			st := checkLink(linkPath, skill.Path, repo, skill.Name)
+			st.Detail = choice.Reason
+			statuses = append(statuses, st)
 		}
 	}
 
 	return statuses, nil
 }
 
+// unlinkSkill removes a chaparral-managed link or overlay directory for a
+// skill. Returns false if there was nothing of ours to remove.
+func unlinkSkill(org config.Org, repo string, skill config.Skill) (LinkResult, bool) {
+	linkPath := filepath.Join(org.Path, repo, ".claude", "skills", skill.Name)
+	if isOurSymlink(linkPath) {
+		os.Remove(linkPath)
+	} else if isOverlayDir(linkPath) {
+		os.RemoveAll(linkPath)
+	} else {
+		return LinkResult{}, false
+	}
+	return LinkResult{Repo: repo, Skill: skill.Name, Action: "removed"}, true
+}
+
 func linkClaudeMD(org config.Org) []LinkResult {
 	source := org.ClaudeMDPath()
 	dest := filepath.Join(org.Path, "CLAUDE.md")
 
+	if render.IsTemplate(org.Manifest.ClaudeMD) {
+		// Rendered into each repo with the templates; drop a link from before
+		result := LinkResult{Repo: "(org)", Skill: "CLAUDE.md", Action: "skipped", Detail: "rendered into each repo"}
+		if isOurSymlink(dest) {
+			if err := os.Remove(dest); err != nil {
+				return []LinkResult{{Repo: "(org)", Skill: "CLAUDE.md", Action: "error", Detail: err.Error()}}
+			}
+			result.Action = "removed"
+		}
+		return []LinkResult{result}
+	}
+
 	if _, err := os.Stat(source); os.IsNotExist(err) {
 		return []LinkResult{{
 			Repo: "(org)", Skill: "CLAUDE.md", Action: "skipped",
@@ -132,9 +225,66 @@ func linkSkill(org config.Org, repo string, skill config.Skill) LinkResult {
 	}
 
 	dest := filepath.Join(skillsDir, skill.Name)
+	if overlay, ok := materialized(org, repo, skill); ok {
+		return materializeOverlay(org, skill, overlay, dest, repo)
+	}
+
+	// Overlay or templates were removed since the last sync — go back to a plain link
+	if isOverlayDir(dest) {
+		if err := os.RemoveAll(dest); err != nil {
+			return LinkResult{Repo: repo, Skill: skill.Name, Action: "error", Detail: err.Error()}
+		}
+	}
 	return createSymlink(skill.Path, dest, repo, skill.Name)
 }
 
+// syncMCP merges the manifest's MCP servers into every sibling repo.
+// Invalid server entries are reported once and left out of every repo.
+func syncMCP(org config.Org) []LinkResult {
+	if len(org.Manifest.MCP.Servers) == 0 {
+		return nil
+	}
+
+	var results []LinkResult
+	valid, invalid := mcp.ValidServers(org.Manifest.MCP.Servers)
+	for _, name := range mcp.SortedNames(invalid) {
+		results = append(results, LinkResult{
+			Repo: "(org)", Skill: "mcp:" + name, Action: "error", Detail: invalid[name].Error(),
+		})
+	}
+	if len(valid) == 0 {
+		return results
+	}
+
+	for _, repo := range org.Repos {
+		sr, err := mcp.SyncRepo(filepath.Join(org.Path, repo), valid)
+		result := LinkResult{Repo: repo, Skill: ".mcp.json"}
+		switch {
+		case err != nil:
+			result.Action = "error"
+			result.Detail = err.Error()
+		case !sr.Changed():
+			result.Action = "exists"
+		case sr.Created:
+			result.Action = "created"
+			result.Detail = "added " + strings.Join(sr.Added, ", ")
+		default:
+			result.Action = "updated"
+			var parts []string
+			if len(sr.Added) > 0 {
+				parts = append(parts, "added "+strings.Join(sr.Added, ", "))
+			}
+			if len(sr.Updated) > 0 {
+				parts = append(parts, "updated "+strings.Join(sr.Updated, ", "))
+			}
+			result.Detail = strings.Join(parts, "; ")
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
 func createSymlink(source, dest, repo, name string) LinkResult {
 	// Check if destination already exists
 	info, err := os.Lstat(dest)
@@ -0,0 +1,289 @@
+package linker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// setupOrg creates an org with a brand repo, the given skills, and a single
+// sibling repo named "toyon".
+func setupOrg(t *testing.T, skills map[string]string) config.Org {
+	t.Helper()
+	orgDir := t.TempDir()
+
+	for name, content := range skills {
+		skillDir := filepath.Join(orgDir, "brand", "org", "skills", name)
+		if err := os.MkdirAll(skillDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Join(orgDir, "toyon"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	return config.Org{
+		Name:      "test-org",
+		Path:      orgDir,
+		BrandRepo: "brand",
+		Manifest: config.Manifest{
+			Org:       "test-org",
+			SkillsDir: "org/skills",
+		},
+		Repos: []string{"toyon"},
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func findStatus(statuses []LinkStatus, repo, skill string) LinkStatus {
+	for _, st := range statuses {
+		if st.Repo == repo && st.Skill == skill {
+			return st
+		}
+	}
+	return LinkStatus{}
+}
+
+func TestSyncOrg_LinksSkills(t *testing.T) {
+	org := setupOrg(t, map[string]string{"brand-voice": "---\nname: brand-voice\n---\n"})
+
+	if _, err := SyncOrg(org); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	statuses, err := StatusOrg(org)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := findStatus(statuses, "toyon", "brand-voice"); st.State != "linked" {
+		t.Errorf("brand-voice state = %q, want linked", st.State)
+	}
+}
+
+func TestSyncOrg_Overlay(t *testing.T) {
+	org := setupOrg(t, map[string]string{"brand-voice": "---\nname: brand-voice\n---\n# Brand voice\n"})
+	skillPath := filepath.Join(org.SkillsPath(), "brand-voice")
+	writeFile(t, filepath.Join(skillPath, "examples.md"), "upstream examples\n")
+
+	overlay := filepath.Join(org.Path, "toyon", ".claude", "skill-overlays", "brand-voice")
+	writeFile(t, filepath.Join(overlay, "SKILL.md"), "## Toyon specifics\n")
+	writeFile(t, filepath.Join(overlay, "glossary.md"), "toyon terms\n")
+
+	if _, err := SyncOrg(org); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dest := filepath.Join(org.Path, "toyon", ".claude", "skills", "brand-voice")
+	skillMD, err := os.ReadFile(filepath.Join(dest, "SKILL.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(skillMD), "# Brand voice") || !strings.Contains(string(skillMD), "## Toyon specifics") {
+		t.Errorf("SKILL.md should combine brand and overlay, got:\n%s", skillMD)
+	}
+
+	for _, name := range []string{"examples.md", "glossary.md"} {
+		if _, err := os.Stat(filepath.Join(dest, name)); err != nil {
+			t.Errorf("expected %s in materialized skill: %v", name, err)
+		}
+	}
+
+	statuses, _ := StatusOrg(org)
+	if st := findStatus(statuses, "toyon", "brand-voice"); st.State != "linked+overlay" {
+		t.Errorf("state = %q, want linked+overlay", st.State)
+	}
+
+	// An upstream edit makes the combined SKILL.md stale until the next sync
+	writeFile(t, filepath.Join(skillPath, "SKILL.md"), "---\nname: brand-voice\n---\n# Brand voice v2\n")
+	statuses, _ = StatusOrg(org)
+	if st := findStatus(statuses, "toyon", "brand-voice"); st.State != "stale" {
+		t.Errorf("state after upstream edit = %q, want stale", st.State)
+	}
+
+	if _, err := SyncOrg(org); err != nil {
+		t.Fatal(err)
+	}
+	skillMD, _ = os.ReadFile(filepath.Join(dest, "SKILL.md"))
+	if !strings.Contains(string(skillMD), "# Brand voice v2") {
+		t.Errorf("upstream edit didn't flow into overlay, got:\n%s", skillMD)
+	}
+}
+
+func TestSyncOrg_SkillTemplates(t *testing.T) {
+	org := setupOrg(t, map[string]string{"brand-voice": "---\nname: brand-voice\n---\n# Brand voice\n"})
+	skillPath := filepath.Join(org.SkillsPath(), "brand-voice")
+	writeFile(t, filepath.Join(skillPath, "context.md.tmpl"), "Writing for {{.Repo}}.\n")
+
+	if _, err := SyncOrg(org); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dest := filepath.Join(org.Path, "toyon", ".claude", "skills", "brand-voice")
+	context, err := os.ReadFile(filepath.Join(dest, "context.md"))
+	if err != nil {
+		t.Fatalf("expected the rendered template: %v", err)
+	}
+	if !strings.Contains(string(context), "Writing for toyon.") {
+		t.Errorf("unexpected rendered content:\n%s", context)
+	}
+	if _, err := os.Lstat(filepath.Join(dest, "context.md.tmpl")); !os.IsNotExist(err) {
+		t.Error("expected the template itself to be left out")
+	}
+	if target, err := os.Readlink(filepath.Join(dest, "SKILL.md")); err != nil || target != filepath.Join(skillPath, "SKILL.md") {
+		t.Errorf("expected SKILL.md linked to the brand skill, got %q (%v)", target, err)
+	}
+
+	statuses, _ := StatusOrg(org)
+	if st := findStatus(statuses, "toyon", "brand-voice"); st.State != "linked+overlay" {
+		t.Errorf("state = %q, want linked+overlay", st.State)
+	}
+
+	// Editing the template leaves the rendered file out of date until the next sync
+	writeFile(t, filepath.Join(skillPath, "context.md.tmpl"), "Writing for {{.Repo}}, carefully.\n")
+	statuses, _ = StatusOrg(org)
+	if st := findStatus(statuses, "toyon", "brand-voice"); st.State != "stale" {
+		t.Errorf("state after editing the template = %q, want stale", st.State)
+	}
+}
+
+func TestSyncOrg_ClaudeMDTemplate(t *testing.T) {
+	org := setupOrg(t, map[string]string{"brand-voice": "---\nname: brand-voice\n---\n"})
+	org.Manifest.ClaudeMD = "org/CLAUDE.md.tmpl"
+	writeFile(t, org.ClaudeMDPath(), "# {{.Org}} in {{.Repo}}\n")
+
+	if _, err := SyncOrg(org); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(org.Path, "CLAUDE.md")); !os.IsNotExist(err) {
+		t.Error("expected no org-level CLAUDE.md link for a template")
+	}
+	data, err := os.ReadFile(filepath.Join(org.Path, "toyon", "CLAUDE.md"))
+	if err != nil {
+		t.Fatalf("expected CLAUDE.md rendered into the repo: %v", err)
+	}
+	if !strings.Contains(string(data), "# test-org in toyon") {
+		t.Errorf("unexpected rendered CLAUDE.md:\n%s", data)
+	}
+
+	if _, err := UnlinkOrg(org); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(org.Path, "toyon", "CLAUDE.md")); !os.IsNotExist(err) {
+		t.Error("expected unlink to remove the rendered CLAUDE.md")
+	}
+}
+
+func TestSyncOrg_OverlayRemoved(t *testing.T) {
+	org := setupOrg(t, map[string]string{"brand-voice": "---\nname: brand-voice\n---\n"})
+	overlay := filepath.Join(org.Path, "toyon", ".claude", "skill-overlays", "brand-voice")
+	writeFile(t, filepath.Join(overlay, "SKILL.md"), "extra\n")
+
+	if _, err := SyncOrg(org); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(overlay); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SyncOrg(org); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, _ := StatusOrg(org)
+	if st := findStatus(statuses, "toyon", "brand-voice"); st.State != "linked" {
+		t.Errorf("state after removing overlay = %q, want linked", st.State)
+	}
+}
+
+func TestUnlinkOrg_RemovesOverlay(t *testing.T) {
+	org := setupOrg(t, map[string]string{"brand-voice": "---\nname: brand-voice\n---\n"})
+	overlay := filepath.Join(org.Path, "toyon", ".claude", "skill-overlays", "brand-voice")
+	writeFile(t, filepath.Join(overlay, "SKILL.md"), "extra\n")
+
+	if _, err := SyncOrg(org); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UnlinkOrg(org); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(org.Path, "toyon", ".claude", "skills", "brand-voice")
+	if _, err := os.Lstat(dest); !os.IsNotExist(err) {
+		t.Error("materialized overlay should be removed by unlink")
+	}
+	if _, err := os.Stat(filepath.Join(overlay, "SKILL.md")); err != nil {
+		t.Error("unlink must not touch the repo's overlay source")
+	}
+}
+
+func TestSyncOrg_LinksDependencies(t *testing.T) {
+	org := setupOrg(t, map[string]string{
+		"release-notes": "---\nname: release-notes\nrequires: [brand-voice]\n---\n",
+		"brand-voice":   "---\nname: brand-voice\n---\n",
+		"go-review":     "---\nname: go-review\n---\n",
+	})
+	org.Manifest.Repos = map[string]config.RepoConfig{
+		"toyon": {Skills: []string{"release-notes"}},
+	}
+
+	if _, err := SyncOrg(org); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	statuses, err := StatusOrg(org)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st := findStatus(statuses, "toyon", "release-notes"); st.State != "linked" {
+		t.Errorf("release-notes state = %q, want linked", st.State)
+	}
+	st := findStatus(statuses, "toyon", "brand-voice")
+	if st.State != "linked" || st.Detail != "required by release-notes" {
+		t.Errorf("brand-voice = %q (%s), want linked (required by release-notes)", st.State, st.Detail)
+	}
+	if st := findStatus(statuses, "toyon", "go-review"); st.State != "skipped" {
+		t.Errorf("go-review state = %q, want skipped", st.State)
+	}
+}
+
+func TestSyncOrg_WhenCondition(t *testing.T) {
+	org := setupOrg(t, map[string]string{
+		"go-review": "---\nname: go-review\n---\n",
+	})
+	org.Manifest.Skills = map[string]config.SkillConfig{
+		"go-review": {When: &config.Condition{Exists: "go.mod"}},
+	}
+
+	if _, err := SyncOrg(org); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	statuses, _ := StatusOrg(org)
+	st := findStatus(statuses, "toyon", "go-review")
+	if st.State != "skipped" || st.Detail != "condition not met: go.mod not found" {
+		t.Errorf("go-review = %q (%s), want skipped (condition not met: go.mod not found)", st.State, st.Detail)
+	}
+
+	writeFile(t, filepath.Join(org.Path, "toyon", "go.mod"), "module toyon\n")
+	if _, err := SyncOrg(org); err != nil {
+		t.Fatal(err)
+	}
+	statuses, _ = StatusOrg(org)
+	if st := findStatus(statuses, "toyon", "go-review"); st.State != "linked" {
+		t.Errorf("go-review state once go.mod exists = %q, want linked", st.State)
+	}
+}
@@ -0,0 +1,269 @@
+package linker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+	"github.com/manzanita-research/chaparral/internal/render"
+)
+
+// overlayMarker is written into every materialized skill directory so
+// chaparral can tell its own directories apart from hand-made ones.
+const overlayMarker = ".chaparral-overlay"
+
+// overlayEntry is one expected entry in a materialized skill directory:
+// either a symlink to a brand or overlay file, or generated content.
+type overlayEntry struct {
+	target  string // symlink target, empty for generated files
+	content string // generated file content
+}
+
+// overlayDir returns the repo-local overlay directory for a skill, or "" if
+// the repo has no overlay for it.
+func overlayDir(org config.Org, repo, skill string) string {
+	dir := filepath.Join(org.Path, repo, ".claude", "skill-overlays", skill)
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir
+	}
+	return ""
+}
+
+// materialized reports whether a skill is linked into a repo as a real
+// directory rather than a symlink: when the repo has an overlay for it, or
+// the skill has *.tmpl files to render for the repo. overlay is "" without
+// an overlay.
+func materialized(org config.Org, repo string, skill config.Skill) (overlay string, ok bool) {
+	overlay = overlayDir(org, repo, skill.Name)
+	return overlay, overlay != "" || hasTemplates(skill.Path)
+}
+
+// hasTemplates reports whether a directory has *.tmpl files at its top level.
+func hasTemplates(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if !e.IsDir() && render.IsTemplate(e.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+// materializeOverlay builds dest as a real directory combining the brand skill
+// with a repo's overlay, if it has one, and rendering its templates. Files are
+// symlinked so upstream and repo edits both flow through; SKILL.md is the
+// brand file with the overlay's SKILL.md appended, and each *.tmpl file is
+// rendered for the repo under its name without the extension.
+func materializeOverlay(org config.Org, skill config.Skill, overlay, dest, repo string) LinkResult {
+	detail := "overlay"
+	if overlay == "" {
+		detail = "rendered"
+	}
+	expected, err := expectedOverlay(org, repo, skill, overlay)
+	if err != nil {
+		return LinkResult{Repo: repo, Skill: skill.Name, Action: "error", Detail: err.Error()}
+	}
+
+	action := "created"
+	info, err := os.Lstat(dest)
+	if err == nil {
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			// Plain link from before the overlay existed — replace it
+			os.Remove(dest)
+			action = "updated"
+		case isOverlayDir(dest):
+			if overlayMatches(dest, expected) {
+				return LinkResult{Repo: repo, Skill: skill.Name, Action: "exists", Detail: detail}
+			}
+			if err := os.RemoveAll(dest); err != nil {
+				return LinkResult{Repo: repo, Skill: skill.Name, Action: "error", Detail: err.Error()}
+			}
+			action = "updated"
+		default:
+			return LinkResult{
+				Repo: repo, Skill: skill.Name, Action: "skipped",
+				Detail: "non-symlink file exists at destination",
+			}
+		}
+	}
+
+	if err := writeOverlay(dest, expected); err != nil {
+		return LinkResult{Repo: repo, Skill: skill.Name, Action: "error", Detail: err.Error()}
+	}
+	return LinkResult{Repo: repo, Skill: skill.Name, Action: action, Detail: detail}
+}
+
+// checkOverlay reports the state of a skill that has a repo overlay or
+// templates, including whether its rendered files are out of date.
+func checkOverlay(org config.Org, skill config.Skill, overlay, dest, repo string) LinkStatus {
+	status := LinkStatus{Repo: repo, Skill: skill.Name, LinkTarget: overlay}
+	if overlay == "" {
+		status.LinkTarget = skill.Path
+	}
+
+	info, err := os.Lstat(dest)
+	switch {
+	case err != nil:
+		status.State = "missing"
+	case info.Mode()&os.ModeSymlink != 0:
+		// Still a plain link — sync will materialize the overlay
+		status.State = "stale"
+	case !isOverlayDir(dest):
+		status.State = "conflict"
+	default:
+		expected, err := expectedOverlay(org, repo, skill, overlay)
+		if err == nil && overlayMatches(dest, expected) {
+			status.State = "linked+overlay"
+		} else {
+			status.State = "stale"
+		}
+	}
+	return status
+}
+
+// expectedOverlay computes the entries a materialized directory should hold.
+// Overlay files win over brand files of the same name, and templates are
+// rendered with the repo's context.
+func expectedOverlay(org config.Org, repo string, skill config.Skill, overlay string) (map[string]overlayEntry, error) {
+	marker := fmt.Sprintf("skill: %s\n", skill.Path)
+	if overlay != "" {
+		marker += fmt.Sprintf("overlay: %s\n", overlay)
+	}
+	entries := map[string]overlayEntry{
+		overlayMarker: {content: marker},
+	}
+
+	brandEntries, err := os.ReadDir(skill.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", skill.Path, err)
+	}
+	for _, e := range brandEntries {
+		entries[e.Name()] = overlayEntry{target: filepath.Join(skill.Path, e.Name())}
+	}
+
+	if overlay != "" {
+		overlayEntries, err := os.ReadDir(overlay)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", overlay, err)
+		}
+		for _, e := range overlayEntries {
+			if e.Name() == overlayMarker {
+				continue
+			}
+			entries[e.Name()] = overlayEntry{target: filepath.Join(overlay, e.Name())}
+		}
+
+		// SKILL.md is the one file that gets combined rather than replaced
+		overlaySkillMD := filepath.Join(overlay, "SKILL.md")
+		if extra, err := os.ReadFile(overlaySkillMD); err == nil {
+			base, err := os.ReadFile(filepath.Join(skill.Path, "SKILL.md"))
+			if err != nil {
+				return nil, fmt.Errorf("reading brand SKILL.md: %w", err)
+			}
+			entries["SKILL.md"] = overlayEntry{content: combineSkillMD(string(base), string(extra))}
+		}
+	}
+
+	// Render each template in place of a file of the same name
+	var templates []string
+	for name, e := range entries {
+		if e.target != "" && render.IsTemplate(name) {
+			if info, err := os.Stat(e.target); err == nil && !info.IsDir() {
+				templates = append(templates, name)
+			}
+		}
+	}
+	ctx := render.NewContext(org, repo)
+	for _, name := range templates {
+		t := render.Template{
+			Name:   skill.Name + "/" + name,
+			Source: entries[name].target,
+			Dest:   strings.TrimSuffix(name, ".tmpl"),
+		}
+		content, err := render.Render(t, ctx)
+		if err != nil {
+			return nil, err
+		}
+		delete(entries, name)
+		entries[t.Dest] = overlayEntry{content: content}
+	}
+
+	return entries, nil
+}
+
+// combineSkillMD appends the overlay's SKILL.md body to the brand SKILL.md.
+// Frontmatter in the overlay is dropped — the brand skill's metadata wins.
+func combineSkillMD(base, extra string) string {
+	if strings.HasPrefix(extra, "---\n") {
+		if end := strings.Index(extra[4:], "\n---\n"); end >= 0 {
+			extra = extra[4+end+len("\n---\n"):]
+		}
+	}
+	if !strings.HasSuffix(base, "\n") {
+		base += "\n"
+	}
+	return base + "\n" + strings.TrimLeft(extra, "\n")
+}
+
+// writeOverlay creates the materialized directory from scratch.
+func writeOverlay(dest string, entries map[string]overlayEntry) error {
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", dest, err)
+	}
+	for name, e := range entries {
+		path := filepath.Join(dest, name)
+		if e.target != "" {
+			if err := os.Symlink(e.target, path); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.WriteFile(path, []byte(e.content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// overlayMatches compares a materialized directory against its expected entries.
+func overlayMatches(dest string, expected map[string]overlayEntry) bool {
+	actual, err := os.ReadDir(dest)
+	if err != nil || len(actual) != len(expected) {
+		return false
+	}
+	for _, a := range actual {
+		e, ok := expected[a.Name()]
+		if !ok {
+			return false
+		}
+		path := filepath.Join(dest, a.Name())
+		if e.target != "" {
+			target, err := os.Readlink(path)
+			if err != nil || target != e.target {
+				return false
+			}
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != e.content {
+			return false
+		}
+	}
+	return true
+}
+
+// isOverlayDir checks for a real directory carrying the overlay marker.
+func isOverlayDir(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(path, overlayMarker))
+	return err == nil
+}
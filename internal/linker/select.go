@@ -0,0 +1,77 @@
+package linker
+
+import (
+	"path/filepath"
+
+	"github.com/manzanita-research/chaparral/internal/condition"
+	"github.com/manzanita-research/chaparral/internal/config"
+	"github.com/manzanita-research/chaparral/internal/skillmeta"
+)
+
+// SkillChoice records whether a skill gets linked into a repo, and why.
+type SkillChoice struct {
+	Skill    config.Skill
+	Selected bool
+	Reason   string // e.g. "required by release-notes", "condition not met: go.mod not found"
+}
+
+// SelectSkills decides which skills a repo gets. A repo with a "skills" list
+// in the manifest gets only those. Otherwise a skill with a "when" condition
+// is selected where the condition holds, and every other skill is selected.
+// Skills required by a selected skill are selected too, transitively.
+func SelectSkills(org config.Org, repo string, skills []config.Skill) []SkillChoice {
+	choices := make([]SkillChoice, len(skills))
+	index := make(map[string]int)
+	requires := make(map[string][]string)
+	for i, skill := range skills {
+		choices[i] = SkillChoice{Skill: skill}
+		index[skill.Name] = i
+		if fm, err := skillmeta.ParseFrontmatter(filepath.Join(skill.Path, "SKILL.md")); err == nil {
+			requires[skill.Name] = fm.Requires
+		}
+	}
+
+	list := org.Manifest.Repos[repo].Skills
+	wanted := make(map[string]bool)
+	for _, name := range list {
+		wanted[name] = true
+	}
+
+	repoPath := filepath.Join(org.Path, repo)
+	var queue []string
+	for i, skill := range skills {
+		switch when := org.Manifest.Skills[skill.Name].When; {
+		case list != nil && !wanted[skill.Name]:
+			choices[i].Reason = "not selected for this repo"
+		case list == nil && when != nil:
+			ok, reason := condition.Evaluate(*when, repoPath)
+			if !ok {
+				choices[i].Reason = "condition not met: " + reason
+				continue
+			}
+			choices[i].Selected = true
+			choices[i].Reason = reason
+			queue = append(queue, skill.Name)
+		default:
+			choices[i].Selected = true
+			queue = append(queue, skill.Name)
+		}
+	}
+
+	// Pull in dependencies of everything selected
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, dep := range requires[name] {
+			i, ok := index[dep]
+			if !ok || choices[i].Selected {
+				continue
+			}
+			choices[i].Selected = true
+			choices[i].Reason = "required by " + name
+			queue = append(queue, dep)
+		}
+	}
+
+	return choices
+}
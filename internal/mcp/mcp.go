@@ -0,0 +1,256 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+const mcpFile = ".mcp.json"
+
+var serverNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ServerStatus describes one managed server in a single repo's .mcp.json.
+type ServerStatus struct {
+	Repo   string
+	Server string
+	State  string // "present", "missing", "drifted", "invalid"
+	Detail string
+}
+
+// SyncResult describes what SyncRepo changed in a repo's .mcp.json.
+type SyncResult struct {
+	Created bool     // true if .mcp.json didn't exist before
+	Added   []string // servers that were missing
+	Updated []string // servers whose config had drifted
+}
+
+// Changed returns true if SyncRepo wrote anything.
+func (r SyncResult) Changed() bool {
+	return len(r.Added) > 0 || len(r.Updated) > 0
+}
+
+// ValidateServer checks a single server entry for the fields Claude Code needs.
+func ValidateServer(name string, s config.MCPServer) error {
+	if !serverNameRe.MatchString(name) {
+		return fmt.Errorf("server name %q must contain only letters, digits, - and _", name)
+	}
+
+	switch s.Type {
+	case "", "stdio":
+		if s.Command == "" {
+			return fmt.Errorf("server %q has no command", name)
+		}
+		if s.URL != "" {
+			return fmt.Errorf("server %q sets url on a stdio server (use type http or sse)", name)
+		}
+	case "http", "sse":
+		if s.URL == "" {
+			return fmt.Errorf("server %q of type %s has no url", name, s.Type)
+		}
+		if s.Command != "" {
+			return fmt.Errorf("server %q sets command on a %s server", name, s.Type)
+		}
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("server %q has invalid url %q", name, s.URL)
+		}
+	default:
+		return fmt.Errorf("server %q has unknown type %q (want stdio, http, or sse)", name, s.Type)
+	}
+
+	return nil
+}
+
+// ValidServers splits the manifest's servers into valid entries and
+// per-server validation errors, both keyed by server name. Range over
+// SortedNames for a stable order.
+func ValidServers(servers map[string]config.MCPServer) (map[string]config.MCPServer, map[string]error) {
+	valid := make(map[string]config.MCPServer)
+	invalid := make(map[string]error)
+	for name, s := range servers {
+		if err := ValidateServer(name, s); err != nil {
+			invalid[name] = err
+			continue
+		}
+		valid[name] = s
+	}
+	return valid, invalid
+}
+
+// SyncRepo merges the managed servers into a repo's .mcp.json. Servers the
+// repo defines itself are left untouched; managed servers that are missing or
+// have drifted are written. The file is only rewritten when something changed.
+func SyncRepo(repoPath string, servers map[string]config.MCPServer) (SyncResult, error) {
+	var result SyncResult
+	path := filepath.Join(repoPath, mcpFile)
+
+	top, existing, err := readMCPFile(path)
+	if os.IsNotExist(err) {
+		result.Created = true
+	} else if err != nil {
+		return result, err
+	}
+
+	for _, name := range SortedNames(servers) {
+		state, err := compareServer(existing[name], servers[name])
+		if err != nil {
+			return result, err
+		}
+		if state == "present" {
+			continue
+		}
+
+		data, err := json.Marshal(servers[name])
+		if err != nil {
+			return result, fmt.Errorf("encoding server %s: %w", name, err)
+		}
+		existing[name] = data
+
+		if state == "missing" {
+			result.Added = append(result.Added, name)
+		} else {
+			result.Updated = append(result.Updated, name)
+		}
+	}
+
+	if !result.Changed() {
+		return result, nil
+	}
+
+	serversData, err := json.Marshal(existing)
+	if err != nil {
+		return result, fmt.Errorf("encoding mcpServers: %w", err)
+	}
+	top["mcpServers"] = serversData
+
+	out, err := json.MarshalIndent(top, "", "  ")
+	if err != nil {
+		return result, fmt.Errorf("encoding %s: %w", mcpFile, err)
+	}
+	if err := os.WriteFile(path, append(out, '\n'), 0644); err != nil {
+		return result, fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	return result, nil
+}
+
+// StatusRepo reports the state of each managed server in a repo's .mcp.json
+// without changing anything.
+func StatusRepo(repo, repoPath string, servers map[string]config.MCPServer) ([]ServerStatus, error) {
+	_, existing, err := readMCPFile(filepath.Join(repoPath, mcpFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var statuses []ServerStatus
+	for _, name := range SortedNames(servers) {
+		state, err := compareServer(existing[name], servers[name])
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, ServerStatus{Repo: repo, Server: name, State: state})
+	}
+	return statuses, nil
+}
+
+// StatusOrg reports managed server state for every sibling repo in an org.
+// Invalid manifest entries are reported once with Repo set to "(org)".
+func StatusOrg(org config.Org) []ServerStatus {
+	valid, invalid := ValidServers(org.Manifest.MCP.Servers)
+
+	var statuses []ServerStatus
+	for _, name := range SortedNames(invalid) {
+		statuses = append(statuses, ServerStatus{
+			Repo: "(org)", Server: name, State: "invalid", Detail: invalid[name].Error(),
+		})
+	}
+
+	if len(valid) == 0 {
+		return statuses
+	}
+
+	for _, repo := range org.Repos {
+		repoStatuses, err := StatusRepo(repo, filepath.Join(org.Path, repo), valid)
+		if err != nil {
+			for _, name := range SortedNames(valid) {
+				statuses = append(statuses, ServerStatus{
+					Repo: repo, Server: name, State: "invalid", Detail: err.Error(),
+				})
+			}
+			continue
+		}
+		statuses = append(statuses, repoStatuses...)
+	}
+	return statuses
+}
+
+// readMCPFile parses .mcp.json into its top-level fields and its mcpServers
+// map, keeping every entry as raw JSON so unknown fields survive a rewrite.
+// A missing file returns empty maps along with the os.IsNotExist error.
+func readMCPFile(path string) (map[string]json.RawMessage, map[string]json.RawMessage, error) {
+	top := make(map[string]json.RawMessage)
+	servers := make(map[string]json.RawMessage)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return top, servers, err
+	}
+
+	if err := json.Unmarshal(data, &top); err != nil {
+		return top, servers, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if raw, ok := top["mcpServers"]; ok {
+		if err := json.Unmarshal(raw, &servers); err != nil {
+			return top, servers, fmt.Errorf("parsing mcpServers in %s: %w", path, err)
+		}
+		if servers == nil {
+			servers = make(map[string]json.RawMessage)
+		}
+	}
+	return top, servers, nil
+}
+
+// compareServer returns "missing", "drifted", or "present" for a repo's raw
+// entry against the managed definition. Comparison is structural, so key
+// order and whitespace don't count as drift.
+func compareServer(raw json.RawMessage, want config.MCPServer) (string, error) {
+	if raw == nil {
+		return "missing", nil
+	}
+
+	wantData, err := json.Marshal(want)
+	if err != nil {
+		return "", fmt.Errorf("encoding server: %w", err)
+	}
+
+	var have, expected any
+	if err := json.Unmarshal(raw, &have); err != nil {
+		return "drifted", nil
+	}
+	if err := json.Unmarshal(wantData, &expected); err != nil {
+		return "", fmt.Errorf("decoding server: %w", err)
+	}
+
+	if reflect.DeepEqual(have, expected) {
+		return "present", nil
+	}
+	return "drifted", nil
+}
+
+// SortedNames returns a map's server names in order.
+func SortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
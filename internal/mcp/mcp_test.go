@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+func managedServers() map[string]config.MCPServer {
+	return map[string]config.MCPServer{
+		"docs":   {Command: "npx", Args: []string{"-y", "@acme/docs-mcp"}},
+		"issues": {Type: "http", URL: "https://issues.example.com/mcp"},
+	}
+}
+
+func readServers(t *testing.T, repoPath string) map[string]map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(repoPath, ".mcp.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f struct {
+		MCPServers map[string]map[string]any `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatal(err)
+	}
+	return f.MCPServers
+}
+
+func TestSyncRepo_CreatesFile(t *testing.T) {
+	dir := t.TempDir()
+
+	result, err := SyncRepo(dir, managedServers())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Created {
+		t.Error("expected Created=true for a new .mcp.json")
+	}
+	if len(result.Added) != 2 {
+		t.Errorf("expected 2 added servers, got %v", result.Added)
+	}
+
+	servers := readServers(t, dir)
+	if servers["docs"]["command"] != "npx" {
+		t.Errorf("docs command = %v, want npx", servers["docs"]["command"])
+	}
+}
+
+func TestSyncRepo_PreservesRepoServers(t *testing.T) {
+	dir := t.TempDir()
+	existing := `{
+  "mcpServers": {
+    "local-db": {"command": "./bin/db-mcp"},
+    "docs": {"command": "npx", "args": ["old"]}
+  },
+  "otherSetting": true
+}`
+	if err := os.WriteFile(filepath.Join(dir, ".mcp.json"), []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SyncRepo(dir, managedServers())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Updated) != 1 || result.Updated[0] != "docs" {
+		t.Errorf("expected docs to be updated, got %v", result.Updated)
+	}
+	if len(result.Added) != 1 || result.Added[0] != "issues" {
+		t.Errorf("expected issues to be added, got %v", result.Added)
+	}
+
+	servers := readServers(t, dir)
+	if _, ok := servers["local-db"]; !ok {
+		t.Error("repo-specific server local-db was dropped")
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, ".mcp.json"))
+	var top map[string]any
+	json.Unmarshal(data, &top)
+	if top["otherSetting"] != true {
+		t.Error("unrelated top-level field was dropped")
+	}
+}
+
+func TestSyncRepo_UpToDate(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := SyncRepo(dir, managedServers()); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := SyncRepo(dir, managedServers())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Changed() {
+		t.Errorf("expected no changes on second sync, got %+v", result)
+	}
+}
+
+func TestStatusRepo(t *testing.T) {
+	dir := t.TempDir()
+	existing := `{"mcpServers": {"docs": {"command": "npx", "args": ["old"]}}}`
+	if err := os.WriteFile(filepath.Join(dir, ".mcp.json"), []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := StatusRepo("toyon", dir, managedServers())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{"docs": "drifted", "issues": "missing"}
+	for _, st := range statuses {
+		if st.State != want[st.Server] {
+			t.Errorf("%s state = %q, want %q", st.Server, st.State, want[st.Server])
+		}
+	}
+}
+
+func TestValidateServer(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  config.MCPServer
+		wantErr bool
+	}{
+		{"docs", config.MCPServer{Command: "npx"}, false},
+		{"docs", config.MCPServer{Type: "stdio", Command: "npx"}, false},
+		{"issues", config.MCPServer{Type: "http", URL: "https://example.com/mcp"}, false},
+		{"docs", config.MCPServer{}, true},
+		{"docs", config.MCPServer{URL: "https://example.com/mcp"}, true},
+		{"issues", config.MCPServer{Type: "sse"}, true},
+		{"issues", config.MCPServer{Type: "http", URL: "not a url"}, true},
+		{"issues", config.MCPServer{Type: "websocket", URL: "https://example.com"}, true},
+		{"bad name", config.MCPServer{Command: "npx"}, true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateServer(tt.name, tt.server)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateServer(%q, %+v) error = %v, wantErr %v", tt.name, tt.server, err, tt.wantErr)
+		}
+	}
+}
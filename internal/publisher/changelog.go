@@ -0,0 +1,120 @@
+package publisher
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+const changelogFile = "CHANGELOG.md"
+
+// changelogMarker is the first line of every changelog chaparral maintains.
+// Changelogs without it are left alone.
+const changelogMarker = "<!-- generated by chaparral — a section is added for each published version -->"
+
+const changelogHeader = changelogMarker + "\n# Changelog\n"
+
+// ChangelogEntry is what changed in a skill for one published version.
+type ChangelogEntry struct {
+	Release
+	Date    time.Time
+	Commits []Commit // newest first; empty if the changes were never committed
+	First   bool     // the skill's first published version
+}
+
+// Lines describes the entry as markdown list items.
+func (e ChangelogEntry) Lines() []string {
+	var lines []string
+	for _, c := range e.Commits {
+		lines = append(lines, fmt.Sprintf("- %s (%s)", c.Subject, shortHash(c.Hash)))
+	}
+	if len(lines) == 0 {
+		if e.First {
+			return []string{"- Initial release"}
+		}
+		return []string{"- Content changed; no commits recorded"}
+	}
+	return lines
+}
+
+// section renders the entry as a skill changelog section.
+func (e ChangelogEntry) section() string {
+	return fmt.Sprintf("## %s — %s\n\n%s\n", e.Version, e.Date.Format("2006-01-02"), strings.Join(e.Lines(), "\n"))
+}
+
+// newChangelogEntry collects the commits behind a skill's new version. A skill
+// outside a git repo gets an entry without commits.
+func newChangelogEntry(skill, dest config.Skill, version string, first bool) (*ChangelogEntry, error) {
+	commits, err := history(skill, dest)
+	if err != nil && !errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil, fmt.Errorf("%s: reading changelog commits: %w", skill.Name, err)
+	}
+	return &ChangelogEntry{
+		Release: Release{Skill: skill.Name, Version: version},
+		Date:    time.Now(),
+		Commits: commits,
+		First:   first,
+	}, nil
+}
+
+// marketplaceSection renders entries for several skills as one section of the
+// marketplace changelog.
+func marketplaceSection(entries []ChangelogEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n", entries[0].Date.Format("2006-01-02"))
+	for _, e := range entries {
+		fmt.Fprintf(&b, "\n### %s %s\n\n%s\n", e.Skill, e.Version, strings.Join(e.Lines(), "\n"))
+	}
+	return b.String()
+}
+
+// updateChangelog returns the changelog at path with section added above the
+// existing ones. ok is false if the file exists but wasn't generated by
+// chaparral, in which case it must not be touched.
+func updateChangelog(path, section string) (string, bool, error) {
+	existing, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return changelogHeader + "\n" + section, true, nil
+	} else if err != nil {
+		return "", false, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if !hasChangelogMarker(existing) {
+		return "", false, nil
+	}
+
+	text := string(existing)
+	if i := strings.Index(text, "\n## "); i >= 0 {
+		return text[:i+1] + section + "\n" + text[i+1:], true, nil
+	}
+	return strings.TrimRight(text, "\n") + "\n\n" + section, true, nil
+}
+
+// isGeneratedChangelog reports whether rel, relative to a skill directory, is
+// a changelog chaparral maintains.
+func isGeneratedChangelog(skillPath, rel string) bool {
+	if rel != changelogFile {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(skillPath, rel))
+	return err == nil && hasChangelogMarker(data)
+}
+
+// hasChangelogMarker reports whether data starts with the changelog marker.
+func hasChangelogMarker(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(changelogMarker))
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
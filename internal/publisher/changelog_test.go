@@ -0,0 +1,107 @@
+package publisher
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+func TestUpdateChangelog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "CHANGELOG.md")
+
+	content, ok, err := updateChangelog(path, "## 0.1.0 — 2026-01-01\n\n- Initial release\n")
+	if err != nil || !ok {
+		t.Fatalf("updateChangelog = %v, %v", ok, err)
+	}
+	if !strings.HasPrefix(content, changelogHeader) {
+		t.Errorf("expected generated header, got:\n%s", content)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	content, ok, err = updateChangelog(path, "## 0.1.1 — 2026-01-02\n\n- fix: typo (abc1234)\n")
+	if err != nil || !ok {
+		t.Fatalf("updateChangelog = %v, %v", ok, err)
+	}
+	newer := strings.Index(content, "## 0.1.1")
+	older := strings.Index(content, "## 0.1.0")
+	if newer < 0 || older < 0 || newer > older {
+		t.Errorf("expected 0.1.1 above 0.1.0, got:\n%s", content)
+	}
+
+	if err := os.WriteFile(path, []byte("# Our changelog\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := updateChangelog(path, "## 0.2.0\n"); ok {
+		t.Error("expected a hand-written changelog to be left alone")
+	}
+}
+
+func TestWriteManifests_Changelogs(t *testing.T) {
+	org, skills := setupOrg(t)
+	brandPath := filepath.Join(org.Path, org.BrandRepo)
+
+	written, err := WriteManifests(org, skills, Options{})
+	if err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	paths := make(map[string]bool)
+	for _, w := range written {
+		paths[w.Path] = true
+	}
+	skillLog := filepath.Join(org.Manifest.SkillsDir, "test-skill", "CHANGELOG.md")
+	marketLog := filepath.Join(".claude-plugin", "CHANGELOG.md")
+	if !paths[skillLog] || !paths[marketLog] {
+		t.Fatalf("expected both changelogs to be written, got %v", written)
+	}
+
+	data, err := os.ReadFile(filepath.Join(brandPath, marketLog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "### test-skill 0.1.0") || !strings.Contains(string(data), "- Initial release") {
+		t.Errorf("unexpected marketplace changelog:\n%s", data)
+	}
+
+	// Writing the changelog doesn't count as a content change
+	changes, err := DiffManifests(org, skills, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range changes {
+		if c.Kind != "unchanged" {
+			t.Errorf("expected %s unchanged, got %s", c.Path, c.Kind)
+		}
+	}
+}
+
+func TestPendingChangelog_Commits(t *testing.T) {
+	dir := initTestRepo(t)
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, repo, dir, "skills/voice/SKILL.md", "---\nname: voice\ndescription: d\n---\n", "feat(voice): add skill")
+	commitFile(t, repo, dir, "skills/voice/plugin.json", `{"name":"voice","version":"0.1.0","skills":"./"}`, "publish marketplace v0.1.0")
+	commitFile(t, repo, dir, "skills/voice/SKILL.md", "---\nname: voice\ndescription: d\n---\nBe warm.\n", "feat(voice): warmer tone")
+
+	skill := config.Skill{Name: "voice", Path: filepath.Join(dir, "skills", "voice")}
+	entries, err := PendingChangelog(config.Org{}, []config.Skill{skill}, Options{Bump: BumpAuto})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Version != "0.2.0" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	lines := entries[0].Lines()
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "- feat(voice): warmer tone (") {
+		t.Errorf("unexpected lines %v", lines)
+	}
+}
@@ -4,64 +4,292 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+	"regexp"
+	"slices"
 	"strings"
-	"time"
+	"text/template"
 
 	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+
+	"github.com/manzanita-research/chaparral/internal/config"
 )
 
 // ErrNoChanges is returned when CommitAndPush finds nothing to commit.
 var ErrNoChanges = errors.New("no changes to commit")
 
-// CommitAndPush stages the written files, commits with a structured message,
-// and pushes to origin using system git. Returns ErrNoChanges if the working
-// tree is clean.
-func CommitAndPush(brandRepoPath string, writtenFiles []WrittenFile, version string) error {
+// defaultMessage is the publish commit message when the manifest doesn't set
+// publish.message.
+const defaultMessage = "publish {{if .Releases}}{{.Tags}}{{else}}marketplace{{end}}"
+
+// authorRe matches a commit author in "Name <email>" form.
+var authorRe = regexp.MustCompile(`^[^<>]+ <[^<>]+>$`)
+
+// CommitAndPush stages the written files, commits them, tags the commit with
+// an annotated "<skill>@v<version>" tag for each release, and pushes the
+// commit and tags to origin together. Committing, tagging and pushing use
+// system git, so the user's identity, signing setup, hooks and auth all
+// apply; publish settings can override the author and message and ask for
+// signing. If the push fails the tags are deleted again, so a rerun can
+// create them. Returns ErrNoChanges if the working tree is clean.
+func CommitAndPush(brandRepoPath string, writtenFiles []WrittenFile, releases []Release, settings config.PublishConfig) error {
 	repo, err := git.PlainOpen(brandRepoPath)
 	if err != nil {
 		return fmt.Errorf("opening repo at %s: %w", brandRepoPath, err)
 	}
+	if err := checkTagsFree(repo, releases); err != nil {
+		return err
+	}
+	if err := checkAuthor(settings.Author); err != nil {
+		return err
+	}
+	message, err := commitMessage(settings.Message, releases)
+	if err != nil {
+		return err
+	}
+
+	tagRefs, err := commitAndTag(brandRepoPath, repo, writtenFiles, releases, message, settings)
+	if err != nil {
+		return err
+	}
+	if err := push(brandRepoPath, append([]string{"HEAD"}, tagRefs...)...); err != nil {
+		deleteTags(brandRepoPath, tagRefs)
+		return err
+	}
+	return nil
+}
+
+// checkTagsFree refuses, before anything is committed, to publish a release
+// that was already tagged.
+func checkTagsFree(repo *git.Repository, releases []Release) error {
+	for _, r := range releases {
+		if _, err := repo.Tag(r.Tag()); err == nil {
+			return fmt.Errorf("tag %s already exists", r.Tag())
+		}
+	}
+	return nil
+}
 
+// commitAndTag stages the written files, commits them on the checked-out
+// branch and tags each release at the new commit. Returns the tag refs to
+// push, or ErrNoChanges if there was nothing to commit.
+func commitAndTag(repoPath string, repo *git.Repository, writtenFiles []WrittenFile, releases []Release, message string, settings config.PublishConfig) ([]string, error) {
 	w, err := repo.Worktree()
 	if err != nil {
-		return fmt.Errorf("getting worktree: %w", err)
+		return nil, fmt.Errorf("getting worktree: %w", err)
 	}
 
-	// Stage each written file
+	// Stage each written or removed file. A removed file that was never
+	// committed has nothing to stage.
 	for _, f := range writtenFiles {
+		if f.Removed {
+			if _, err := w.Remove(f.Path); err != nil && !errors.Is(err, index.ErrEntryNotFound) {
+				return nil, fmt.Errorf("staging removal of %s: %w", f.Path, err)
+			}
+			continue
+		}
 		if _, err := w.Add(f.Path); err != nil {
-			return fmt.Errorf("staging %s: %w", f.Path, err)
+			return nil, fmt.Errorf("staging %s: %w", f.Path, err)
 		}
 	}
+	staged, err := hasStaged(w)
+	if err != nil {
+		return nil, err
+	}
+	if !staged {
+		return nil, ErrNoChanges
+	}
 
 	// Commit
-	msg := fmt.Sprintf("publish marketplace v%s", version)
-	_, err = w.Commit(msg, &git.CommitOptions{
-		Author: &object.Signature{
-			Name:  "chaparral",
-			Email: "chaparral@local",
-			When:  time.Now(),
-		},
-	})
+	identity := identityArgs(repoPath)
+	args := slices.Concat(identity, []string{"commit", "-m", message})
+	if settings.Author != "" {
+		args = append(args, "--author", settings.Author)
+	}
+	if settings.Sign {
+		args = append(args, "-S")
+	}
+	if _, err := runGit(repoPath, args...); err != nil {
+		return nil, fmt.Errorf("committing: %w", err)
+	}
+
+	// Tag each release at the publish commit
+	tags := make([]PendingTag, len(releases))
+	for i, r := range releases {
+		tags[i] = PendingTag{Release: r, Commit: "HEAD"}
+	}
+	return tagReleases(repoPath, tags, settings)
+}
+
+// tagReleases creates an annotated tag — signed if settings ask for signing
+// — for each release at its commit, and returns the refs to push. If a tag
+// can't be created, the ones before it are deleted.
+func tagReleases(repoPath string, tags []PendingTag, settings config.PublishConfig) ([]string, error) {
+	tagFlag := "-a"
+	if settings.Sign {
+		tagFlag = "-s"
+	}
+	identity := identityArgs(repoPath)
+	var refs []string
+	for _, t := range tags {
+		args := slices.Concat(identity, []string{"tag", tagFlag, "-m", fmt.Sprintf("%s v%s", t.Skill, t.Version), t.Tag(), t.Commit})
+		if _, err := runGit(repoPath, args...); err != nil {
+			deleteTags(repoPath, refs)
+			return nil, fmt.Errorf("tagging %s: %w", t.Tag(), err)
+		}
+		refs = append(refs, "refs/tags/"+t.Tag())
+	}
+	return refs, nil
+}
+
+// PushTags tags releases that were committed without being tagged — see
+// Plan.PendingTags — and pushes the tags to origin. With withHead, the
+// checked-out branch is pushed along with them, for a publish commit whose
+// push failed. If the push fails the tags are deleted again.
+func PushTags(repoPath string, tags []PendingTag, settings config.PublishConfig, withHead bool) error {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return fmt.Errorf("opening repo at %s: %w", repoPath, err)
+	}
+	releases := make([]Release, len(tags))
+	for i, t := range tags {
+		releases[i] = t.Release
+	}
+	if err := checkTagsFree(repo, releases); err != nil {
+		return err
+	}
+
+	refs, err := tagReleases(repoPath, tags, settings)
 	if err != nil {
-		if errors.Is(err, git.ErrEmptyCommit) {
-			return ErrNoChanges
+		return err
+	}
+	pushRefs := refs
+	if withHead {
+		pushRefs = append([]string{"HEAD"}, refs...)
+	}
+	if err := push(repoPath, pushRefs...); err != nil {
+		deleteTags(repoPath, refs)
+		return err
+	}
+	return nil
+}
+
+// deleteTags deletes local tags by ref, best effort: it runs while handling
+// another error, which is the one worth reporting.
+func deleteTags(repoPath string, refs []string) {
+	for _, ref := range refs {
+		runGit(repoPath, "tag", "-d", strings.TrimPrefix(ref, "refs/tags/"))
+	}
+}
+
+// hasStaged reports whether the index differs from HEAD.
+func hasStaged(w *git.Worktree) (bool, error) {
+	status, err := w.Status()
+	if err != nil {
+		return false, fmt.Errorf("reading status: %w", err)
+	}
+	for _, s := range status {
+		if s.Staging != git.Unmodified && s.Staging != git.Untracked {
+			return true, nil
 		}
-		return fmt.Errorf("committing: %w", err)
 	}
+	return false, nil
+}
+
+// identityArgs returns git options that fill in whichever of user.name and
+// user.email the user hasn't configured, so publishing works on machines
+// without a git identity. A configured identity is always used as is.
+func identityArgs(repoPath string) []string {
+	var args []string
+	if name, _ := runGit(repoPath, "config", "user.name"); name == "" {
+		args = append(args, "-c", "user.name=chaparral")
+	}
+	if email, _ := runGit(repoPath, "config", "user.email"); email == "" {
+		args = append(args, "-c", "user.email=chaparral@local")
+	}
+	return args
+}
+
+// push pushes refs to origin atomically using system git — uses whatever
+// auth the user already has configured.
+func push(repoPath string, refs ...string) error {
+	args := append([]string{"push", "--atomic", "origin"}, refs...)
+	if _, err := runGit(repoPath, args...); err != nil {
+		return fmt.Errorf("pushing: %w", err)
+	}
+	return nil
+}
 
-	// Push using system git — uses whatever auth the user already has configured
-	cmd := exec.Command("git", "push", "origin")
-	cmd.Dir = brandRepoPath
+// runGit runs system git in dir and returns its trimmed output. Errors carry
+// git's own output.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("pushing: %s", strings.TrimSpace(string(output)))
+		return "", errors.New(strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitOutput runs system git in dir and returns its output untouched, for
+// output where whitespace matters. Errors carry git's stderr.
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
 	}
+	return string(output), nil
+}
 
+// checkAuthor refuses a publish.author git wouldn't accept, before anything
+// is committed.
+func checkAuthor(author string) error {
+	if author != "" && !authorRe.MatchString(author) {
+		return fmt.Errorf("publish.author %q should look like \"Name <email>\"", author)
+	}
 	return nil
 }
 
+// commitData is what a publish.message template is executed with.
+type commitData struct {
+	Releases []Release // each with .Skill, .Version and .Tag
+	Tags     string    // the release tags, comma-separated
+}
+
+// commitMessage renders the publish commit message from tmpl, or from the
+// default — which names every release, or just the marketplace when no skill
+// versions changed — if tmpl is empty.
+func commitMessage(tmpl string, releases []Release) (string, error) {
+	if tmpl == "" {
+		tmpl = defaultMessage
+	}
+	t, err := template.New("message").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parsing publish.message: %w", err)
+	}
+
+	tags := make([]string, len(releases))
+	for i, r := range releases {
+		tags[i] = r.Tag()
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, commitData{Releases: releases, Tags: strings.Join(tags, ", ")}); err != nil {
+		return "", fmt.Errorf("rendering publish.message: %w", err)
+	}
+	message := strings.TrimSpace(b.String())
+	if message == "" {
+		return "", fmt.Errorf("publish.message renders an empty commit message")
+	}
+	return message, nil
+}
+
 // RemoteURL returns the origin remote URL for display in confirmation prompts.
 func RemoteURL(brandRepoPath string) (string, error) {
 	repo, err := git.PlainOpen(brandRepoPath)
@@ -2,12 +2,17 @@ package publisher
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/manzanita-research/chaparral/internal/config"
 )
 
 // initTestRepo creates a git repo with an initial commit in a temp directory.
@@ -53,7 +58,7 @@ func TestCommitAndPush_NoChanges(t *testing.T) {
 	// Try to commit with files that don't exist or are unchanged
 	err := CommitAndPush(dir, []WrittenFile{
 		{Path: "README.md", IsNew: false},
-	}, "0.1.0")
+	}, nil, config.PublishConfig{})
 
 	if err == nil {
 		t.Fatal("expected error for no changes")
@@ -99,7 +104,7 @@ func TestCommitAndPush_StagesAndCommits(t *testing.T) {
 	// CommitAndPush will fail on push (no remote) but should succeed on commit
 	err := CommitAndPush(dir, []WrittenFile{
 		{Path: ".claude-plugin/marketplace.json", IsNew: true},
-	}, "0.1.0")
+	}, []Release{{Skill: "test-skill", Version: "0.1.0"}}, config.PublishConfig{})
 
 	// We expect a push error since there's no remote configured
 	if err == nil {
@@ -113,8 +118,30 @@ func TestCommitAndPush_StagesAndCommits(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if commit.Message != "publish marketplace v0.1.0" {
-		t.Errorf("expected commit message 'publish marketplace v0.1.0', got %q", commit.Message)
+	if commit.Message != "publish test-skill@v0.1.0\n" {
+		t.Errorf("expected commit message 'publish test-skill@v0.1.0', got %q", commit.Message)
+	}
+}
+
+func TestCommitAndPush_RemovesUntrackedFile(t *testing.T) {
+	dir := initTestRepo(t)
+	initTestRemote(t, dir)
+
+	// Written by an earlier --write-only, then removed before it was committed
+	writeTestPlugin(t, dir, `{"name":"voice","version":"0.3.0"}`)
+	if err := os.Remove(filepath.Join(dir, "skills", "voice", "plugin.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "marketplace.json"), []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	written := []WrittenFile{
+		{Path: "skills/voice/plugin.json", Removed: true},
+		{Path: "marketplace.json", IsNew: true},
+	}
+	if err := CommitAndPush(dir, written, nil, config.PublishConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 }
 
@@ -126,3 +153,215 @@ func TestRemoteURL_NoRemote(t *testing.T) {
 		t.Fatal("expected error for repo with no remote")
 	}
 }
+
+func TestCommitAndPush_TagsReleases(t *testing.T) {
+	dir := initTestRepo(t)
+	remoteDir := t.TempDir()
+	if _, err := git.PlainInit(remoteDir, true); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CreateRemote(&gitconfig.RemoteConfig{Name: "origin", URLs: []string{remoteDir}}); err != nil {
+		t.Fatal(err)
+	}
+
+	pluginPath := filepath.Join(dir, "skills", "voice", "plugin.json")
+	if err := os.MkdirAll(filepath.Dir(pluginPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pluginPath, []byte(`{"name":"voice","version":"0.3.0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	releases := []Release{{Skill: "voice", Version: "0.3.0"}}
+	err = CommitAndPush(dir, []WrittenFile{{Path: "skills/voice/plugin.json", IsNew: true}}, releases, config.PublishConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	remote, err := git.PlainOpen(remoteDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := remote.Tag("voice@v0.3.0")
+	if err != nil {
+		t.Fatalf("expected tag on remote: %v", err)
+	}
+	tag, err := remote.TagObject(ref.Hash())
+	if err != nil {
+		t.Fatalf("expected an annotated tag: %v", err)
+	}
+	head, _ := repo.Head()
+	if tag.Target != head.Hash() {
+		t.Errorf("tag points at %s, want publish commit %s", tag.Target, head.Hash())
+	}
+
+	// Publishing the same release again is refused before committing
+	err = CommitAndPush(dir, nil, releases, config.PublishConfig{})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected an already-exists error, got %v", err)
+	}
+}
+
+func TestCommitAndPush_PushFailureDeletesTags(t *testing.T) {
+	dir := initTestRepo(t)
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing")
+	if _, err := repo.CreateRemote(&gitconfig.RemoteConfig{Name: "origin", URLs: []string{missing}}); err != nil {
+		t.Fatal(err)
+	}
+	writeTestPlugin(t, dir, `{"name":"voice","version":"0.3.0"}`)
+
+	releases := []Release{{Skill: "voice", Version: "0.3.0"}}
+	err = CommitAndPush(dir, []WrittenFile{{Path: "skills/voice/plugin.json", IsNew: true}}, releases, config.PublishConfig{})
+	if err == nil {
+		t.Fatal("expected a push error")
+	}
+	if _, err := repo.Tag("voice@v0.3.0"); err == nil {
+		t.Error("expected the unpushed tag to be deleted")
+	}
+}
+
+// publishTestCommit writes a plugin.json into a repo with a bare remote and
+// publishes it with settings, returning the repo and the publish commit.
+func publishTestCommit(t *testing.T, dir string, settings config.PublishConfig) (*git.Repository, *object.Commit) {
+	t.Helper()
+	initTestRemote(t, dir)
+	writeTestPlugin(t, dir, `{"name":"voice","version":"0.3.0"}`)
+	releases := []Release{{Skill: "voice", Version: "0.3.0"}}
+	if err := CommitAndPush(dir, []WrittenFile{{Path: "skills/voice/plugin.json", IsNew: true}}, releases, settings); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, _ := repo.Head()
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, commit
+}
+
+// setGitConfig sets a key in the repo's own git config.
+func setGitConfig(t *testing.T, dir, key, value string) {
+	t.Helper()
+	if _, err := runGit(dir, "config", key, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCommitAndPush_UsesGitIdentity(t *testing.T) {
+	dir := initTestRepo(t)
+	setGitConfig(t, dir, "user.name", "Ada Lovelace")
+	setGitConfig(t, dir, "user.email", "ada@example.com")
+
+	_, commit := publishTestCommit(t, dir, config.PublishConfig{})
+	if commit.Author.Name != "Ada Lovelace" || commit.Author.Email != "ada@example.com" {
+		t.Errorf("author = %s <%s>, want the git config identity", commit.Author.Name, commit.Author.Email)
+	}
+}
+
+func TestCommitAndPush_FallbackIdentity(t *testing.T) {
+	dir := initTestRepo(t)
+	t.Setenv("HOME", t.TempDir()) // no global identity
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	_, commit := publishTestCommit(t, dir, config.PublishConfig{})
+	if commit.Author.Email != "chaparral@local" {
+		t.Errorf("author email = %q, want chaparral@local", commit.Author.Email)
+	}
+}
+
+func TestCommitAndPush_AuthorOverride(t *testing.T) {
+	dir := initTestRepo(t)
+	setGitConfig(t, dir, "user.name", "Ada Lovelace")
+	setGitConfig(t, dir, "user.email", "ada@example.com")
+
+	_, commit := publishTestCommit(t, dir, config.PublishConfig{Author: "Release Bot <releases@example.com>"})
+	if commit.Author.Name != "Release Bot" || commit.Author.Email != "releases@example.com" {
+		t.Errorf("author = %s <%s>, want the publish.author override", commit.Author.Name, commit.Author.Email)
+	}
+	if commit.Committer.Email != "ada@example.com" {
+		t.Errorf("committer email = %q, want the git config identity", commit.Committer.Email)
+	}
+
+	err := CommitAndPush(dir, nil, nil, config.PublishConfig{Author: "releases@example.com"})
+	if err == nil || !strings.Contains(err.Error(), "Name <email>") {
+		t.Errorf("expected a malformed author error, got %v", err)
+	}
+}
+
+func TestCommitAndPush_MessageTemplate(t *testing.T) {
+	dir := initTestRepo(t)
+	tmpl := "chore(release): {{.Tags}}\n\n{{range .Releases}}- {{.Skill}} {{.Version}}\n{{end}}"
+
+	_, commit := publishTestCommit(t, dir, config.PublishConfig{Message: tmpl})
+	want := "chore(release): voice@v0.3.0\n\n- voice 0.3.0\n"
+	if commit.Message != want {
+		t.Errorf("message = %q, want %q", commit.Message, want)
+	}
+}
+
+func TestCommitMessage(t *testing.T) {
+	releases := []Release{{Skill: "voice", Version: "0.3.0"}, {Skill: "tone", Version: "1.0.0"}}
+	tests := []struct {
+		tmpl     string
+		releases []Release
+		want     string
+		wantErr  bool
+	}{
+		{"", releases, "publish voice@v0.3.0, tone@v1.0.0", false},
+		{"", nil, "publish marketplace", false},
+		{"release{{range .Releases}} {{.Tag}}{{end}}", releases, "release voice@v0.3.0 tone@v1.0.0", false},
+		{"{{.Skills}}", releases, "", true},
+		{"{{if .Releases}}", releases, "", true},
+		{"{{.Tags}}", nil, "", true},
+	}
+	for _, tt := range tests {
+		got, err := commitMessage(tt.tmpl, tt.releases)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("commitMessage(%q) error = %v, wantErr %v", tt.tmpl, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("commitMessage(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+func TestCommitAndPush_Signs(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+	dir := initTestRepo(t)
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key).CombinedOutput(); err != nil {
+		t.Fatalf("generating key: %s", out)
+	}
+	setGitConfig(t, dir, "gpg.format", "ssh")
+	setGitConfig(t, dir, "user.signingkey", key)
+
+	repo, commit := publishTestCommit(t, dir, config.PublishConfig{Sign: true})
+	if !strings.Contains(commit.PGPSignature, "BEGIN SSH SIGNATURE") {
+		t.Errorf("expected an SSH-signed commit, got signature %q", commit.PGPSignature)
+	}
+	ref, err := repo.Tag("voice@v0.3.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := repo.TagObject(ref.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(tag.PGPSignature, "BEGIN SSH SIGNATURE") {
+		t.Errorf("expected an SSH-signed tag, got signature %q", tag.PGPSignature)
+	}
+}
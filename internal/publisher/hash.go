@@ -0,0 +1,34 @@
+package publisher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// hashExclude lists files, relative to the skill directory, that chaparral
+// generates and so don't count as skill content.
+var hashExclude = map[string]bool{
+	"plugin.json": true,
+}
+
+// ContentHash returns a hash of the files publishing copies from a skill
+// directory — see skillFiles — so dotfiles and the generated files are left
+// out: those in hashExclude, and the changelog if chaparral maintains it
+// (see isGeneratedChangelog). It changes when any of those files is added,
+// removed, renamed or edited, and not when files are merely touched.
+func ContentHash(skillPath string) (string, error) {
+	files, err := skillFiles(skillPath)
+	if err != nil {
+		return "", fmt.Errorf("hashing %s: %w", skillPath, err)
+	}
+
+	h := sha256.New()
+	for _, rel := range sortedKeys(files) {
+		data := files[rel].data
+		// Length-prefix names and contents so boundaries can't be shifted
+		fmt.Fprintf(h, "%d:%s\x00%d:", len(rel), rel, len(data))
+		h.Write(data)
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,72 @@
+package publisher
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestContentHash(t *testing.T) {
+	dir := t.TempDir()
+	skill := setupSkillDir(t, dir, "test-skill")
+
+	first, err := ContentHash(skill.Path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(first, "sha256:") {
+		t.Errorf("expected sha256: prefix, got %s", first)
+	}
+
+	// plugin.json is generated, so writing it doesn't change the hash
+	if err := os.WriteFile(filepath.Join(skill.Path, "plugin.json"), []byte(`{"version":"0.1.0"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ContentHash(skill.Path); got != first {
+		t.Errorf("plugin.json changed the hash: %s != %s", got, first)
+	}
+
+	// Dotfiles aren't published, so they don't count either
+	if err := os.WriteFile(filepath.Join(skill.Path, ".DS_Store"), []byte("finder"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(skill.Path, ".cache"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skill.Path, ".cache", "state"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ContentHash(skill.Path); got != first {
+		t.Errorf("a dotfile changed the hash: %s != %s", got, first)
+	}
+
+	// Any other file does
+	if err := os.MkdirAll(filepath.Join(skill.Path, "scripts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skill.Path, "scripts", "run.sh"), []byte("echo hi\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ContentHash(skill.Path); got == first {
+		t.Error("expected adding a file to change the hash")
+	}
+}
+
+func TestNextVersion_UnchangedContent(t *testing.T) {
+	dir := t.TempDir()
+	skill := setupSkillDir(t, dir, "my-skill")
+
+	hash, err := ContentHash(skill.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugin := `{"name":"my-skill","version":"1.2.3","contentHash":"` + hash + `"}`
+	if err := os.WriteFile(filepath.Join(skill.Path, "plugin.json"), []byte(plugin), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := NextVersion(skill, BumpPatch); err != nil || got != "1.2.3" {
+		t.Errorf("expected 1.2.3 for unchanged content, got %s", got)
+	}
+}
@@ -0,0 +1,244 @@
+package publisher
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// conventionalRe matches a conventional commit subject: "type(scope)!: ...".
+var conventionalRe = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^)]*)\))?(!)?:\s`)
+
+// Commit is a commit that changed a skill since it was last published.
+type Commit struct {
+	Hash    string
+	Subject string
+	Message string
+	Level   Level // the bump the commit calls for
+}
+
+// ClassifyCommit returns the bump a conventional commit message calls for:
+// major for "type!:" or a BREAKING CHANGE footer, minor for feat, and patch
+// for anything else, including messages that aren't conventional.
+func ClassifyCommit(message string) Level {
+	subject, _, _ := strings.Cut(message, "\n")
+	m := conventionalRe.FindStringSubmatch(subject)
+	switch {
+	case m != nil && m[3] == "!":
+		return BumpMajor
+	case strings.Contains(message, "\nBREAKING CHANGE:"), strings.Contains(message, "\nBREAKING-CHANGE:"):
+		return BumpMajor
+	case m != nil && strings.EqualFold(m[1], "feat"):
+		return BumpMinor
+	}
+	return BumpPatch
+}
+
+// SkillCommits walks the history of the git repo containing skillPath, newest
+// first, and returns the commits that changed the skill since its last
+// publish: the commit its newest release tag points at if HEAD can reach it
+// or, for skills without one, the last commit that changed its plugin.json.
+// Merge commits are skipped.
+func SkillCommits(skillPath string) ([]Commit, error) {
+	repo, rel, err := openRepoAt(skillPath)
+	if err != nil {
+		return nil, err
+	}
+	_, tagged, hasTag, err := reachableTag(repo, filepath.Base(skillPath))
+	if err != nil {
+		return nil, err
+	}
+
+	pluginPath := rel + "/plugin.json"
+	return skillLog(repo, rel, func(c, parent *object.Commit) bool {
+		if hasTag {
+			return c.Hash == tagged.Hash
+		}
+		return entryHash(parent, pluginPath) != entryHash(c, pluginPath)
+	})
+}
+
+// history returns the commits behind a skill's next version. When the skill
+// is published from its own directory that's SkillCommits; when it's copied
+// to a publish target, it's the commits in the brand repo since the one the
+// target's plugin.json records it was copied from. Targets published before
+// that was recorded fall back to the commits made after the last publish.
+func history(skill, dest config.Skill) ([]Commit, error) {
+	if skill.Path == dest.Path {
+		return SkillCommits(skill.Path)
+	}
+	repo, rel, err := openRepoAt(skill.Path)
+	if err != nil {
+		return nil, err
+	}
+	if source, ok := sourceCommit(repo, dest); ok {
+		return skillLog(repo, rel, func(c, _ *object.Commit) bool {
+			return c.Hash == source
+		})
+	}
+
+	since, err := publishedAt(dest)
+	if err != nil {
+		return nil, err
+	}
+	return skillLog(repo, rel, func(c, _ *object.Commit) bool {
+		return !c.Committer.When.After(since)
+	})
+}
+
+// sourceCommit returns the brand repo commit a published copy of a skill was
+// made from, as recorded in its plugin.json. ok is false if none is recorded
+// or it isn't in HEAD's history.
+func sourceCommit(repo *git.Repository, dest config.Skill) (plumbing.Hash, bool) {
+	pm, ok := readPublished(filepath.Join(dest.Path, "plugin.json"))
+	if !ok || pm.SourceCommit == "" {
+		return plumbing.ZeroHash, false
+	}
+	source, err := repo.CommitObject(plumbing.NewHash(pm.SourceCommit))
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+	tip, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+	if reachable, err := source.IsAncestor(tip); err != nil || !reachable {
+		return plumbing.ZeroHash, false
+	}
+	return source.Hash, true
+}
+
+// skillLog walks a repo's history from HEAD, newest first, until stop
+// returns true, and returns the non-merge commits that changed the directory
+// at rel.
+func skillLog(repo *git.Repository, rel string, stop func(c, parent *object.Commit) bool) ([]Commit, error) {
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, nil // no commits yet
+	} else if err != nil {
+		return nil, fmt.Errorf("reading HEAD: %w", err)
+	}
+	iter, err := repo.Log(&git.LogOptions{From: head.Hash(), Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+
+	var commits []Commit
+	err = iter.ForEach(func(c *object.Commit) error {
+		var parent *object.Commit
+		if c.NumParents() > 0 {
+			if parent, err = c.Parent(0); err != nil {
+				return err
+			}
+		}
+		if stop(c, parent) {
+			return storer.ErrStop
+		}
+		if c.NumParents() > 1 || entryHash(parent, rel) == entryHash(c, rel) {
+			return nil
+		}
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		commits = append(commits, Commit{
+			Hash:    c.Hash.String(),
+			Subject: strings.TrimSpace(subject),
+			Message: c.Message,
+			Level:   ClassifyCommit(c.Message),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+	return commits, nil
+}
+
+// InferBump returns the largest bump called for by a skill's commits since
+// its last publish, or a patch bump if there are none.
+func InferBump(skillPath string) (Level, error) {
+	commits, err := SkillCommits(skillPath)
+	if err != nil {
+		return "", err
+	}
+	return largestBump(commits), nil
+}
+
+// largestBump returns the largest bump any of the commits calls for, or a
+// patch bump if there are none.
+func largestBump(commits []Commit) Level {
+	level := BumpPatch
+	for _, c := range commits {
+		switch {
+		case c.Level == BumpMajor:
+			return BumpMajor
+		case c.Level == BumpMinor:
+			level = BumpMinor
+		}
+	}
+	return level
+}
+
+// openRepoAt opens the git repo containing path and returns path relative to
+// its root.
+func openRepoAt(path string) (*git.Repository, string, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, "", fmt.Errorf("opening repo for %s: %w", path, err)
+	}
+	rel, err := repoRelative(repo, path)
+	if err != nil {
+		return nil, "", err
+	}
+	return repo, rel, nil
+}
+
+// repoRelative returns path relative to the repo's worktree root, with
+// forward slashes.
+func repoRelative(repo *git.Repository, path string) (string, error) {
+	w, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("getting worktree: %w", err)
+	}
+	root, err := filepath.EvalSymlinks(w.Filesystem.Root())
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// entryHash returns the hash of the tree entry at path in a commit, or the
+// zero hash if the commit is nil or the path doesn't exist in it.
+func entryHash(c *object.Commit, path string) plumbing.Hash {
+	if c == nil {
+		return plumbing.ZeroHash
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return plumbing.ZeroHash
+	}
+	entry, err := tree.FindEntry(path)
+	if err != nil {
+		return plumbing.ZeroHash
+	}
+	return entry.Hash
+}
@@ -0,0 +1,112 @@
+package publisher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// commitFile writes a file in a repo and commits it with the given message.
+func commitFile(t *testing.T, repo *git.Repository, dir, rel, content, message string) {
+	t.Helper()
+	path := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Add(rel); err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@test.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClassifyCommit(t *testing.T) {
+	tests := []struct {
+		message string
+		want    Level
+	}{
+		{"feat(brand-voice): add tone examples", BumpMinor},
+		{"feat: add glossary", BumpMinor},
+		{"fix(brand-voice): typo", BumpPatch},
+		{"docs: reword intro", BumpPatch},
+		{"refactor(brand-voice)!: rename sections", BumpMajor},
+		{"fix: trim body\n\nBREAKING CHANGE: drops the legacy section", BumpMajor},
+		{"Update SKILL.md", BumpPatch},
+	}
+	for _, tt := range tests {
+		if got := ClassifyCommit(tt.message); got != tt.want {
+			t.Errorf("ClassifyCommit(%q) = %s, want %s", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestSkillCommits_SinceLastPublish(t *testing.T) {
+	dir := initTestRepo(t)
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitFile(t, repo, dir, "skills/voice/SKILL.md", "---\nname: voice\n---\n", "feat(voice): add skill")
+	commitFile(t, repo, dir, "skills/voice/plugin.json", `{"version":"0.1.0"}`, "publish marketplace v0.1.0")
+	commitFile(t, repo, dir, "skills/voice/SKILL.md", "---\nname: voice\n---\nBe warm.\n", "fix(voice): tone")
+	commitFile(t, repo, dir, "skills/other/SKILL.md", "---\nname: other\n---\n", "feat!: unrelated skill")
+	commitFile(t, repo, dir, "skills/voice/examples.md", "# Examples\n", "feat(voice): add examples")
+
+	skillPath := filepath.Join(dir, "skills", "voice")
+	commits, err := SkillCommits(skillPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits since publish, got %+v", commits)
+	}
+	if commits[0].Subject != "feat(voice): add examples" || commits[1].Subject != "fix(voice): tone" {
+		t.Errorf("unexpected commits %+v", commits)
+	}
+
+	level, err := InferBump(skillPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if level != BumpMinor {
+		t.Errorf("InferBump = %s, want minor", level)
+	}
+}
+
+func TestNextVersion_Auto(t *testing.T) {
+	dir := initTestRepo(t)
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, repo, dir, "skills/voice/SKILL.md", "---\nname: voice\n---\n", "feat(voice): add skill")
+	commitFile(t, repo, dir, "skills/voice/plugin.json", `{"name":"voice","version":"1.4.2","skills":"./"}`, "publish marketplace v1.4.2")
+	commitFile(t, repo, dir, "skills/voice/SKILL.md", "---\nname: voice\n---\nNew rules.\n", "refactor(voice)!: restructure")
+
+	skill := config.Skill{Name: "voice", Path: filepath.Join(dir, "skills", "voice")}
+	got, err := NextVersion(skill, BumpAuto)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "2.0.0" {
+		t.Errorf("NextVersion = %s, want 2.0.0", got)
+	}
+}
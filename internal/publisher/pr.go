@@ -0,0 +1,154 @@
+package publisher
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// branchPrefix starts the name of every branch chaparral publishes on.
+const branchPrefix = "chaparral/publish-"
+
+// githubRemoteRe matches GitHub remotes in https, ssh and scp-like forms and
+// captures "owner/repo".
+var githubRemoteRe = regexp.MustCompile(`^(?:https://|ssh://git@|git@)github\.com[:/]([^/]+/[^/]+?)(?:\.git)?/?$`)
+
+// PullRequest is a publish branch pushed for review.
+type PullRequest struct {
+	Branch string
+	Base   string // the branch that was checked out, which the PR targets
+	Title  string
+	URL    string // a compare URL for GitHub remotes; empty otherwise
+	Output string // what the PR command printed, if one ran
+}
+
+// CommitAndOpenPR publishes through a pull request instead of pushing to the
+// checked-out branch, for repos whose main branch is protected. It commits
+// the written files on a new chaparral/publish-… branch, pushes the branch,
+// and switches back to the original branch. The releases aren't tagged: the
+// branch may never merge, or merge as a different commit, so they're tagged
+// by the first publish after the merge — see Plan.PendingTags.
+// If committing or pushing fails, the branch is deleted again. If settings.PRCommand is set, it's run with sh and the PR details in its
+// environment — CHAPARRAL_BRANCH, CHAPARRAL_BASE, CHAPARRAL_TITLE and
+// CHAPARRAL_BODY — so something like `gh pr create` can open the PR. The
+// commit is made as CommitAndPush makes it, and the PR is titled with its
+// subject line.
+func CommitAndOpenPR(repoPath string, writtenFiles []WrittenFile, releases []Release, settings config.PublishConfig) (PullRequest, error) {
+	repo, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("opening repo at %s: %w", repoPath, err)
+	}
+	if err := checkTagsFree(repo, releases); err != nil {
+		return PullRequest{}, err
+	}
+	if err := checkAuthor(settings.Author); err != nil {
+		return PullRequest{}, err
+	}
+	message, err := commitMessage(settings.Message, releases)
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("reading HEAD: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return PullRequest{}, fmt.Errorf("HEAD is detached; check out the branch the PR should target")
+	}
+	pr := PullRequest{
+		Branch: publishBranch(releases, time.Now()),
+		Base:   head.Name().Short(),
+		Title:  subject(message),
+	}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName(pr.Branch), false); err == nil {
+		return PullRequest{}, fmt.Errorf("branch %s already exists", pr.Branch)
+	}
+
+	// Switch with system git so uncommitted changes come along to the branch
+	if _, err := runGit(repoPath, "checkout", "-b", pr.Branch); err != nil {
+		return PullRequest{}, fmt.Errorf("creating branch %s: %w", pr.Branch, err)
+	}
+	_, err = commitAndTag(repoPath, repo, writtenFiles, nil, message, settings)
+	if _, backErr := runGit(repoPath, "checkout", pr.Base); backErr != nil {
+		if err == nil {
+			err = fmt.Errorf("switching back to %s: %w", pr.Base, backErr)
+		}
+		return PullRequest{}, err
+	}
+	if err == nil {
+		err = push(repoPath, "refs/heads/"+pr.Branch)
+	}
+	if err != nil {
+		// Nothing was pushed, and a rerun needs the branch name free
+		runGit(repoPath, "branch", "-D", pr.Branch)
+		return PullRequest{}, err
+	}
+
+	if remoteURL, err := RemoteURL(repoPath); err == nil {
+		pr.URL = compareURL(remoteURL, pr.Base, pr.Branch)
+	}
+	if settings.PRCommand != "" {
+		if pr.Output, err = runPRCommand(repoPath, settings.PRCommand, pr, releases); err != nil {
+			return pr, err
+		}
+	}
+	return pr, nil
+}
+
+// publishBranch names the branch for a publish: the release for a single
+// skill, or a timestamp when several skills (or none) are released together.
+func publishBranch(releases []Release, now time.Time) string {
+	if len(releases) == 1 {
+		return branchPrefix + releases[0].Skill + "-v" + releases[0].Version
+	}
+	return branchPrefix + now.Format("20060102-150405")
+}
+
+// subject returns the first line of a commit message.
+func subject(message string) string {
+	line, _, _ := strings.Cut(message, "\n")
+	return line
+}
+
+// compareURL returns the GitHub page for opening a PR from branch into base,
+// or "" if the remote isn't on GitHub.
+func compareURL(remoteURL, base, branch string) string {
+	m := githubRemoteRe.FindStringSubmatch(remoteURL)
+	if m == nil {
+		return ""
+	}
+	return fmt.Sprintf("https://github.com/%s/compare/%s...%s?expand=1", m[1], base, branch)
+}
+
+// runPRCommand runs the manifest's PR command with the PR details in its
+// environment and returns what it printed.
+func runPRCommand(repoPath, command string, pr PullRequest, releases []Release) (string, error) {
+	var body strings.Builder
+	body.WriteString("Published by chaparral.\n")
+	for _, r := range releases {
+		fmt.Fprintf(&body, "\n- %s", r.Tag())
+	}
+
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = repoPath
+	cmd.Env = append(os.Environ(),
+		"CHAPARRAL_BRANCH="+pr.Branch,
+		"CHAPARRAL_BASE="+pr.Base,
+		"CHAPARRAL_TITLE="+pr.Title,
+		"CHAPARRAL_BODY="+body.String(),
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("running PR command: %s", strings.TrimSpace(string(output)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
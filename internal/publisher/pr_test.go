@@ -0,0 +1,261 @@
+package publisher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// initTestRemote gives the repo at dir an origin that is a bare repo in a
+// temp directory, and returns the bare repo's path.
+func initTestRemote(t *testing.T, dir string) string {
+	t.Helper()
+	remoteDir := t.TempDir()
+	if _, err := git.PlainInit(remoteDir, true); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CreateRemote(&gitconfig.RemoteConfig{Name: "origin", URLs: []string{remoteDir}}); err != nil {
+		t.Fatal(err)
+	}
+	return remoteDir
+}
+
+func writeTestPlugin(t *testing.T, dir, content string) {
+	t.Helper()
+	pluginPath := filepath.Join(dir, "skills", "voice", "plugin.json")
+	if err := os.MkdirAll(filepath.Dir(pluginPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pluginPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCommitAndOpenPR_PushesBranch(t *testing.T) {
+	dir := initTestRepo(t)
+	remoteDir := initTestRemote(t, dir)
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, _ := repo.Head()
+
+	writeTestPlugin(t, dir, `{"name":"voice","version":"0.3.0"}`)
+	releases := []Release{{Skill: "voice", Version: "0.3.0"}}
+	pr, err := CommitAndOpenPR(dir, []WrittenFile{{Path: "skills/voice/plugin.json", IsNew: true}}, releases, config.PublishConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pr.Branch != "chaparral/publish-voice-v0.3.0" {
+		t.Errorf("branch = %q, want chaparral/publish-voice-v0.3.0", pr.Branch)
+	}
+	if pr.Base != "master" {
+		t.Errorf("base = %q, want master", pr.Base)
+	}
+	if pr.URL != "" {
+		t.Errorf("expected no compare URL for a local remote, got %q", pr.URL)
+	}
+
+	// The local repo is back on its branch, which hasn't moved
+	head, _ := repo.Head()
+	if head.Name() != before.Name() || head.Hash() != before.Hash() {
+		t.Errorf("HEAD is %s at %s, want %s at %s", head.Name(), head.Hash(), before.Name(), before.Hash())
+	}
+
+	remote, err := git.PlainOpen(remoteDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch, err := remote.Reference(plumbing.NewBranchReferenceName(pr.Branch), false)
+	if err != nil {
+		t.Fatalf("expected branch on remote: %v", err)
+	}
+	commit, err := remote.CommitObject(branch.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.Message != "publish voice@v0.3.0\n" {
+		t.Errorf("commit message = %q", commit.Message)
+	}
+	if _, err := commit.File("skills/voice/plugin.json"); err != nil {
+		t.Errorf("expected plugin.json in the publish commit: %v", err)
+	}
+	// Releases are tagged once the PR merges, not on its branch
+	if _, err := remote.Tag("voice@v0.3.0"); err == nil {
+		t.Error("expected no tag on remote for an unmerged release")
+	}
+	if _, err := repo.Tag("voice@v0.3.0"); err == nil {
+		t.Error("expected no local tag for an unmerged release")
+	}
+
+	// Publishing the same version again is refused while its branch exists
+	if _, err := CommitAndOpenPR(dir, nil, releases, config.PublishConfig{}); err == nil {
+		t.Error("expected an error republishing the same version")
+	}
+}
+
+func TestCommitAndOpenPR_RunsCommand(t *testing.T) {
+	dir := initTestRepo(t)
+	initTestRemote(t, dir)
+
+	writeTestPlugin(t, dir, `{"name":"voice","version":"1.0.0"}`)
+	releases := []Release{{Skill: "voice", Version: "1.0.0"}}
+	pr, err := CommitAndOpenPR(dir, []WrittenFile{{Path: "skills/voice/plugin.json", IsNew: true}}, releases,
+		config.PublishConfig{PRCommand: `echo "$CHAPARRAL_BRANCH into $CHAPARRAL_BASE: $CHAPARRAL_TITLE"`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "chaparral/publish-voice-v1.0.0 into master: publish voice@v1.0.0"
+	if pr.Output != want {
+		t.Errorf("output = %q, want %q", pr.Output, want)
+	}
+}
+
+func TestCommitAndOpenPR_NoChanges(t *testing.T) {
+	dir := initTestRepo(t)
+	initTestRemote(t, dir)
+
+	_, err := CommitAndOpenPR(dir, nil, nil, config.PublishConfig{})
+	if err != ErrNoChanges {
+		t.Fatalf("expected ErrNoChanges, got %v", err)
+	}
+
+	// The empty branch is cleaned up
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	branches, _ := repo.Branches()
+	branches.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Name().Short() != "master" {
+			t.Errorf("unexpected branch %s left behind", ref.Name().Short())
+		}
+		return nil
+	})
+}
+
+func TestCommitAndOpenPR_FailureDeletesBranch(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		setup func(t *testing.T, dir string)
+	}{
+		{"failing hook", func(t *testing.T, dir string) {
+			initTestRemote(t, dir)
+			hook := filepath.Join(dir, ".git", "hooks", "pre-commit")
+			if err := os.MkdirAll(filepath.Dir(hook), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"failed push", func(t *testing.T, dir string) {
+			repo, err := git.PlainOpen(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			missing := filepath.Join(t.TempDir(), "missing")
+			if _, err := repo.CreateRemote(&gitconfig.RemoteConfig{Name: "origin", URLs: []string{missing}}); err != nil {
+				t.Fatal(err)
+			}
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := initTestRepo(t)
+			tt.setup(t, dir)
+			writeTestPlugin(t, dir, `{"name":"voice","version":"0.3.0"}`)
+
+			releases := []Release{{Skill: "voice", Version: "0.3.0"}}
+			_, err := CommitAndOpenPR(dir, []WrittenFile{{Path: "skills/voice/plugin.json", IsNew: true}}, releases, config.PublishConfig{})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			repo, err := git.PlainOpen(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			head, _ := repo.Head()
+			if head.Name().Short() != "master" {
+				t.Errorf("expected to be back on master, got %s", head.Name().Short())
+			}
+			if _, err := repo.Reference(plumbing.NewBranchReferenceName("chaparral/publish-voice-v0.3.0"), false); err == nil {
+				t.Error("expected the publish branch to be deleted")
+			}
+		})
+	}
+}
+
+func TestCommitAndOpenPR_BadSettings(t *testing.T) {
+	dir := initTestRepo(t)
+	initTestRemote(t, dir)
+	writeTestPlugin(t, dir, `{"name":"voice","version":"0.3.0"}`)
+	written := []WrittenFile{{Path: "skills/voice/plugin.json", IsNew: true}}
+	releases := []Release{{Skill: "voice", Version: "0.3.0"}}
+
+	for _, settings := range []config.PublishConfig{
+		{Author: "releases@example.com"},
+		{Message: "publish {{.Missing}}"},
+	} {
+		if _, err := CommitAndOpenPR(dir, written, releases, settings); err == nil {
+			t.Errorf("expected an error for %+v", settings)
+		}
+	}
+
+	// Both are refused before a branch is made
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	branches, _ := repo.Branches()
+	branches.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Name().Short() != "master" {
+			t.Errorf("unexpected branch %s", ref.Name().Short())
+		}
+		return nil
+	})
+}
+
+func TestPublishBranch(t *testing.T) {
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		releases []Release
+		want     string
+	}{
+		{[]Release{{Skill: "voice", Version: "0.3.0"}}, "chaparral/publish-voice-v0.3.0"},
+		{[]Release{{Skill: "voice", Version: "0.3.0"}, {Skill: "tone", Version: "1.0.0"}}, "chaparral/publish-20260304-050607"},
+		{nil, "chaparral/publish-20260304-050607"},
+	}
+	for _, tt := range tests {
+		if got := publishBranch(tt.releases, now); got != tt.want {
+			t.Errorf("publishBranch(%v) = %q, want %q", tt.releases, got, tt.want)
+		}
+	}
+}
+
+func TestCompareURL(t *testing.T) {
+	want := "https://github.com/manzanita-research/skills/compare/main...chaparral/publish-voice-v0.3.0?expand=1"
+	for _, remote := range []string{
+		"https://github.com/manzanita-research/skills.git",
+		"https://github.com/manzanita-research/skills",
+		"git@github.com:manzanita-research/skills.git",
+		"ssh://git@github.com/manzanita-research/skills.git",
+	} {
+		if got := compareURL(remote, "main", "chaparral/publish-voice-v0.3.0"); got != want {
+			t.Errorf("compareURL(%q) = %q, want %q", remote, got, want)
+		}
+	}
+	if got := compareURL("https://gitlab.com/o/r.git", "main", "b"); got != "" {
+		t.Errorf("expected no URL for a non-GitHub remote, got %q", got)
+	}
+}
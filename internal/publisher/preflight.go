@@ -0,0 +1,206 @@
+package publisher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/manzanita-research/chaparral/internal/validator"
+)
+
+// Check is the outcome of one safety check run before publishing.
+type Check struct {
+	Name   string
+	OK     bool
+	Detail string
+}
+
+// Preflight runs the checks a publish must pass: the skills it publishes
+// validate without errors, and each repo involved — the brand repo and, if
+// it's separate, the publish target — has no uncommitted changes other than
+// the files in changes, and isn't behind origin after a fetch. Skills the
+// publish leaves alone, like internal ones or those filtered out by
+// Options.Skills, don't block it. Every check runs so all problems are
+// reported at once; publishing should go ahead only if they all pass.
+func (p *Plan) Preflight(changes []FileChange) ([]Check, error) {
+	org := p.org
+	results, err := validator.ValidateOrg(org)
+	if err != nil {
+		return nil, err
+	}
+	publishing := make(map[string]bool)
+	for _, ps := range p.skills {
+		if !ps.keep {
+			publishing[ps.skill.Name] = true
+		}
+	}
+	checks := []Check{validationCheck(results, publishing)}
+
+	generated := make(map[string]bool)
+	for _, c := range changes {
+		generated[filepath.ToSlash(c.Path)] = true
+	}
+	brandRepo := filepath.Join(org.Path, org.BrandRepo)
+	if org.PublishesSeparately() {
+		// Skills are copied from the brand repo, so it has to be clean too
+		checks = append(checks, cleanTreeCheck(brandRepo, nil), syncCheck(brandRepo))
+	}
+	publishPath := org.PublishPath()
+	checks = append(checks, cleanTreeCheck(publishPath, generated), syncCheck(publishPath))
+	return checks, nil
+}
+
+// validationCheck passes if none of the skills being published has
+// validation errors.
+func validationCheck(results []validator.ValidationResult, publishing map[string]bool) Check {
+	check := Check{Name: "published skills validate"}
+	if len(publishing) == 0 {
+		check.OK = true
+		check.Detail = "no skills being published"
+		return check
+	}
+	var failing []string
+	for _, r := range results {
+		if publishing[r.Skill] && !r.IsValid() {
+			failing = append(failing, r.Skill)
+		}
+	}
+	if len(failing) > 0 {
+		check.Detail = fmt.Sprintf("errors in %s; run chaparral validate", strings.Join(failing, ", "))
+		return check
+	}
+	check.OK = true
+	check.Detail = fmt.Sprintf("no errors in the %s being published", plural(len(publishing), "skill"))
+	return check
+}
+
+// cleanTreeCheck passes if the repo at repoPath has no staged, unstaged or
+// untracked changes other than to generated files: the paths in generated,
+// relative to the repo root with forward slashes, and any file with
+// chaparral's generated header, like a changelog left by --write-only.
+func cleanTreeCheck(repoPath string, generated map[string]bool) Check {
+	check := Check{Name: filepath.Base(repoPath) + " working tree clean"}
+	output, err := gitOutput(repoPath, "status", "--porcelain=v1", "-z", "--untracked-files=all")
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+
+	var dirty []string
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++ // the next entry is the rename's source
+		}
+		if path := entry[3:]; !generated[path] && !isGeneratedFile(filepath.Join(repoPath, path)) {
+			dirty = append(dirty, path)
+		}
+	}
+	if len(dirty) > 0 {
+		check.Detail = "uncommitted changes to " + listPaths(dirty) + "; commit or stash them first"
+		return check
+	}
+	check.OK = true
+	check.Detail = "no changes outside generated files"
+	return check
+}
+
+// isGeneratedFile reports whether the file at path is a changelog chaparral
+// maintains. Deleted and unreadable files aren't.
+func isGeneratedFile(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && hasChangelogMarker(data)
+}
+
+// syncCheck fetches origin and passes if the checked-out branch has every
+// commit its upstream has. A branch that was never pushed has nothing to be
+// behind.
+func syncCheck(repoPath string) Check {
+	check := Check{Name: filepath.Base(repoPath) + " up to date with origin"}
+	if _, err := runGit(repoPath, "remote", "get-url", "origin"); err != nil {
+		// A local-only repo has nothing to fall behind
+		check.OK = true
+		check.Detail = "no origin remote"
+		return check
+	}
+	if _, err := runGit(repoPath, "fetch", "--quiet", "origin"); err != nil {
+		check.Detail = "fetching: " + err.Error()
+		return check
+	}
+	branch, err := runGit(repoPath, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		check.Detail = "HEAD is detached; check out a branch first"
+		return check
+	}
+
+	upstream, err := runGit(repoPath, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
+	if err != nil {
+		upstream = "origin/" + branch
+		if _, err := runGit(repoPath, "rev-parse", "--verify", "--quiet", "refs/remotes/"+upstream); err != nil {
+			check.OK = true
+			check.Detail = branch + " isn't on origin yet"
+			return check
+		}
+	}
+
+	counts, err := runGit(repoPath, "rev-list", "--left-right", "--count", "HEAD..."+upstream)
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	ahead, behind, err := parseCounts(counts)
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	switch {
+	case behind > 0:
+		check.Detail = fmt.Sprintf("%s is %s behind %s; pull first", branch, plural(behind, "commit"), upstream)
+	case ahead > 0:
+		check.OK = true
+		check.Detail = fmt.Sprintf("%s is %s ahead of %s", branch, plural(ahead, "commit"), upstream)
+	default:
+		check.OK = true
+		check.Detail = branch + " matches " + upstream
+	}
+	return check
+}
+
+// parseCounts reads the "ahead<TAB>behind" output of rev-list --left-right
+// --count.
+func parseCounts(output string) (int, int, error) {
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", output)
+	}
+	ahead, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", output)
+	}
+	behind, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", output)
+	}
+	return ahead, behind, nil
+}
+
+// listPaths names up to three paths and counts the rest.
+func listPaths(paths []string) string {
+	if len(paths) <= 3 {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(paths[:3], ", "), len(paths)-3)
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
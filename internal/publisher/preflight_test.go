@@ -0,0 +1,202 @@
+package publisher
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// gitT runs system git in dir as a test identity, failing the test on error.
+func gitT(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@test.com"}, args...)
+	if _, err := runGit(dir, args...); err != nil {
+		t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+	}
+}
+
+// setupPreflightOrg creates an org whose brand repo is committed and pushed
+// to a bare origin, and returns the org, its skills and the origin's path.
+func setupPreflightOrg(t *testing.T) (config.Org, []config.Skill, string) {
+	t.Helper()
+	org, skills := setupOrg(t)
+	brandPath := filepath.Join(org.Path, org.BrandRepo)
+	remoteDir := t.TempDir()
+
+	gitT(t, remoteDir, "init", "--quiet", "--bare", "-b", "main")
+	gitT(t, brandPath, "init", "--quiet", "-b", "main")
+	gitT(t, brandPath, "remote", "add", "origin", remoteDir)
+	gitT(t, brandPath, "add", "-A")
+	gitT(t, brandPath, "commit", "--quiet", "-m", "initial commit")
+	gitT(t, brandPath, "push", "--quiet", "-u", "origin", "main")
+	return org, skills, remoteDir
+}
+
+// failedChecks returns the checks that didn't pass.
+func failedChecks(checks []Check) []Check {
+	var failed []Check
+	for _, c := range checks {
+		if !c.OK {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// preflightT plans a publish and runs its preflight checks.
+func preflightT(t *testing.T, org config.Org, skills []config.Skill, opts Options) []Check {
+	t.Helper()
+	plan, err := NewPlan(org, skills, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := plan.Diff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks, err := plan.Preflight(changes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return checks
+}
+
+func TestPreflight_Passes(t *testing.T) {
+	org, skills, _ := setupPreflightOrg(t)
+
+	// Manifests written by an earlier --write-only are generated, not dirty
+	if _, err := WriteManifests(org, skills, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	checks := preflightT(t, org, skills, Options{})
+	if len(checks) != 3 {
+		t.Errorf("expected 3 checks, got %d: %v", len(checks), checks)
+	}
+	if failed := failedChecks(checks); len(failed) > 0 {
+		t.Errorf("expected every check to pass, got %v", failed)
+	}
+}
+
+func TestPreflight_DirtyTree(t *testing.T) {
+	org, skills, _ := setupPreflightOrg(t)
+	brandPath := filepath.Join(org.Path, org.BrandRepo)
+	if err := os.WriteFile(filepath.Join(brandPath, "notes.txt"), []byte("todo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	checks := preflightT(t, org, skills, Options{})
+	failed := failedChecks(checks)
+	if len(failed) != 1 || !strings.Contains(failed[0].Name, "working tree") {
+		t.Fatalf("expected only the working tree check to fail, got %v", failed)
+	}
+	if !strings.Contains(failed[0].Detail, "notes.txt") {
+		t.Errorf("expected notes.txt in %q", failed[0].Detail)
+	}
+}
+
+func TestPreflight_BehindOrigin(t *testing.T) {
+	org, skills, remoteDir := setupPreflightOrg(t)
+
+	// Someone else pushes a commit
+	other := t.TempDir()
+	gitT(t, other, "clone", "--quiet", remoteDir, ".")
+	if err := os.WriteFile(filepath.Join(other, "README.md"), []byte("# brand\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitT(t, other, "add", "README.md")
+	gitT(t, other, "commit", "--quiet", "-m", "add readme")
+	gitT(t, other, "push", "--quiet", "origin", "main")
+
+	checks := preflightT(t, org, skills, Options{})
+	failed := failedChecks(checks)
+	if len(failed) != 1 || !strings.Contains(failed[0].Detail, "1 commit behind origin/main") {
+		t.Errorf("expected only the sync check to fail, 1 commit behind, got %v", failed)
+	}
+}
+
+func TestPreflight_ValidationErrors(t *testing.T) {
+	org, skills, _ := setupPreflightOrg(t)
+	brandPath := filepath.Join(org.Path, org.BrandRepo)
+	skillMD := filepath.Join(skills[0].Path, "SKILL.md")
+	if err := os.WriteFile(skillMD, []byte("---\nname: test-skill\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitT(t, brandPath, "commit", "--quiet", "-am", "break skill")
+
+	checks := preflightT(t, org, skills, Options{})
+	failed := failedChecks(checks)
+	if len(failed) != 1 || !strings.Contains(failed[0].Detail, "test-skill") {
+		t.Errorf("expected only validation to fail for test-skill, got %v", failed)
+	}
+}
+
+func TestPreflight_SeparateTarget(t *testing.T) {
+	org, skills, _ := setupPreflightOrg(t)
+	targetPath := filepath.Join(org.Path, "marketplace")
+	if err := os.MkdirAll(targetPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitT(t, targetPath, "init", "--quiet", "-b", "main")
+	org.Manifest.Publish.Target = "marketplace"
+
+	checks := preflightT(t, org, skills, Options{})
+	if len(checks) != 5 {
+		t.Fatalf("expected validation plus two checks per repo, got %v", checks)
+	}
+	// The target has no origin, so there's nothing to be behind
+	if failed := failedChecks(checks); len(failed) > 0 {
+		t.Errorf("expected every check to pass, got %v", failed)
+	}
+
+	// An origin that can't be fetched fails the check
+	gitT(t, targetPath, "remote", "add", "origin", filepath.Join(org.Path, "missing.git"))
+	failed := failedChecks(preflightT(t, org, skills, Options{}))
+	if len(failed) != 1 || failed[0].Name != "marketplace up to date with origin" || !strings.Contains(failed[0].Detail, "fetching") {
+		t.Errorf("expected only the target's sync check to fail, got %v", failed)
+	}
+}
+
+func TestPreflight_BrandWithoutOrigin(t *testing.T) {
+	org, skills, _ := setupPreflightOrg(t)
+	gitT(t, filepath.Join(org.Path, org.BrandRepo), "remote", "remove", "origin")
+	targetPath := filepath.Join(org.Path, "marketplace")
+	if err := os.MkdirAll(targetPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitT(t, targetPath, "init", "--quiet", "-b", "main")
+	org.Manifest.Publish.Target = "marketplace"
+
+	checks := preflightT(t, org, skills, Options{})
+	if failed := failedChecks(checks); len(failed) > 0 {
+		t.Errorf("expected a local-only brand repo to pass, got %v", failed)
+	}
+}
+
+func TestPreflight_ValidationOnlyPublishedSkills(t *testing.T) {
+	org, skills, _ := setupPreflightOrg(t)
+	brandPath := filepath.Join(org.Path, org.BrandRepo)
+	broken := setupSkillDir(t, org.SkillsPath(), "broken-skill")
+	if err := os.WriteFile(filepath.Join(broken.Path, "SKILL.md"), []byte("---\nname: broken-skill\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	skills = append(skills, broken)
+	gitT(t, brandPath, "add", "-A")
+	gitT(t, brandPath, "commit", "--quiet", "-m", "add broken skill")
+
+	// Filtered out by --skill, so it doesn't block the publish
+	checks := preflightT(t, org, skills, Options{Skills: []string{"test-skill"}})
+	if failed := failedChecks(checks); len(failed) > 0 {
+		t.Errorf("expected every check to pass, got %v", failed)
+	}
+	if !strings.Contains(checks[0].Detail, "1 skill being published") {
+		t.Errorf("expected the detail to name what was checked, got %q", checks[0].Detail)
+	}
+
+	checks = preflightT(t, org, skills, Options{})
+	failed := failedChecks(checks)
+	if len(failed) != 1 || !strings.Contains(failed[0].Detail, "broken-skill") {
+		t.Errorf("expected validation to fail for broken-skill, got %v", failed)
+	}
+}
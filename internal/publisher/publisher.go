@@ -3,26 +3,29 @@ package publisher
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
-	"time"
+	"strings"
 
 	"github.com/manzanita-research/chaparral/internal/config"
 	"github.com/manzanita-research/chaparral/internal/generator"
+	"github.com/manzanita-research/chaparral/internal/skillmeta"
 )
 
 const initialVersion = "0.1.0"
 
 // WrittenFile describes a file that was written to disk.
 type WrittenFile struct {
-	Path  string // relative to brand repo root
-	IsNew bool   // true if file didn't exist before
+	Path    string // relative to the publish repo root
+	IsNew   bool   // true if file didn't exist before
+	Removed bool   // true if the file was deleted instead
 }
 
 // FileChange describes a change that would be made to a file.
 type FileChange struct {
-	Path       string // relative to brand repo root
-	Kind       string // "new", "modified", "unchanged"
+	Path       string // relative to the publish repo root
+	Kind       string // "new", "modified", "removed", "unchanged"
 	OldContent string
 	NewContent string
 }
@@ -34,42 +37,145 @@ type FreshnessResult struct {
 	PublishedVersion string // empty if not yet published
 }
 
-// bumpVersion reads the existing plugin.json for a skill and returns the next
-// patch version. Returns "0.1.0" if the file is missing or unparseable.
-func BumpVersion(brandRepoPath, skillsDir, skillName string) string {
-	pluginPath := filepath.Join(brandRepoPath, skillsDir, skillName, "plugin.json")
+// publishedPlugin is the part of an existing plugin.json that versioning needs.
+type publishedPlugin struct {
+	Version      string `json:"version"`
+	ContentHash  string `json:"contentHash"`
+	SourceCommit string `json:"sourceCommit"`
+}
+
+// readPublished reads a skill's existing plugin.json. ok is false if it's
+// missing or unparseable.
+func readPublished(pluginPath string) (publishedPlugin, bool) {
 	data, err := os.ReadFile(pluginPath)
 	if err != nil {
-		return initialVersion
-	}
-	var pm struct {
-		Version string `json:"version"`
+		return publishedPlugin{}, false
 	}
+	var pm publishedPlugin
 	if err := json.Unmarshal(data, &pm); err != nil || pm.Version == "" {
-		return initialVersion
+		return publishedPlugin{}, false
+	}
+	return pm, true
+}
+
+// Options controls how WriteManifests and DiffManifests version skills.
+type Options struct {
+	Bump       Level            // bump for changed skills; patch if empty
+	SkillBumps map[string]Level // per-skill overrides of Bump, by skill name
+	Skills     []string         // publish only these skills; nil means all of them
+}
+
+// only reports whether the Skills filter lets a skill through.
+func (o Options) only(skill string) bool {
+	if o.Skills == nil {
+		return true
+	}
+	for _, name := range o.Skills {
+		if name == skill {
+			return true
+		}
+	}
+	return false
+}
+
+// level returns the bump level for a skill.
+func (o Options) level(skill string) Level {
+	if l, ok := o.SkillBumps[skill]; ok {
+		return l
+	}
+	if o.Bump != "" {
+		return o.Bump
+	}
+	return BumpPatch
+}
+
+// NextVersion returns the version a skill should be published at. A version
+// pinned in SKILL.md frontmatter wins, as long as it isn't older than the
+// published one. Otherwise the version in the existing plugin.json is kept
+// when the skill's content hash still matches the one recorded there, and
+// bumped at level when it doesn't; BumpAuto infers the level from git
+// history. Returns "0.1.0" if plugin.json is missing or unparseable.
+func NextVersion(skill config.Skill, level Level) (string, error) {
+	return nextVersion(skill, skill, level)
+}
+
+// SkillVersion is NextVersion for a skill in an org, reading the published
+// state from wherever the org publishes it.
+func SkillVersion(org config.Org, skill config.Skill, level Level) (string, error) {
+	return nextVersion(skill, publishedSkill(org, skill), level)
+}
+
+// nextVersion works out a version from the skill's source directory and the
+// directory it's published from, which are the same unless the org has a
+// publish target.
+func nextVersion(skill, dest config.Skill, level Level) (string, error) {
+	pm, published := readPublished(filepath.Join(dest.Path, "plugin.json"))
+	current, err := ParseVersion(pm.Version)
+	published = published && err == nil
+
+	fm, err := skillmeta.ParseFrontmatter(filepath.Join(skill.Path, "SKILL.md"))
+	if err == nil && fm.Version != "" {
+		pinned, err := ParseVersion(fm.Version)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", skill.Name, err)
+		}
+		if published && pinned.Compare(current) < 0 {
+			return "", fmt.Errorf("%s: version %s in SKILL.md is older than published %s", skill.Name, pinned, current)
+		}
+		if published && pinned.Compare(current) == 0 && pm.ContentHash != "" {
+			// Republishing a version under new content would leave its
+			// release tag pointing at the old content
+			hash, err := ContentHash(skill.Path)
+			if err != nil {
+				return "", err
+			}
+			if hash != pm.ContentHash {
+				return "", fmt.Errorf("%s: content changed but version %s is already published; bump it in SKILL.md", skill.Name, pinned)
+			}
+		}
+		return pinned.String(), nil
 	}
-	var major, minor, patch int
-	if _, err := fmt.Sscanf(pm.Version, "%d.%d.%d", &major, &minor, &patch); err != nil {
-		return initialVersion
+
+	if !published {
+		return initialVersion, nil
+	}
+	if pm.ContentHash != "" {
+		if hash, err := ContentHash(skill.Path); err == nil && hash == pm.ContentHash {
+			return current.String(), nil
+		}
+	}
+	if level == BumpAuto {
+		commits, err := history(skill, dest)
+		if err != nil {
+			return "", fmt.Errorf("%s: inferring bump: %w", skill.Name, err)
+		}
+		level = largestBump(commits)
 	}
-	return fmt.Sprintf("%d.%d.%d", major, minor, patch+1)
+	return current.Bump(level).String(), nil
 }
 
-// generateVersionedPlugin generates a plugin.json with the next bumped version.
+// generateVersionedPlugin generates a plugin.json at the given version with
+// the skill's content hash and, for a skill copied to a publish target, the
+// brand repo commit it was copied from (source).
 // Returns the JSON content as a string (with trailing newline).
-func generateVersionedPlugin(skill config.Skill, brandRepoPath string, org config.Org) (string, error) {
+func generateVersionedPlugin(skill config.Skill, version, source string) (string, error) {
 	data, err := generator.GeneratePluginJSON(skill)
 	if err != nil {
 		return "", fmt.Errorf("generating plugin for %s: %w", skill.Name, err)
 	}
 
-	version := BumpVersion(brandRepoPath, org.Manifest.SkillsDir, skill.Name)
+	hash, err := ContentHash(skill.Path)
+	if err != nil {
+		return "", err
+	}
 
 	var manifest generator.PluginManifest
 	if err := json.Unmarshal(data, &manifest); err != nil {
 		return "", fmt.Errorf("parsing generated manifest for %s: %w", skill.Name, err)
 	}
 	manifest.Version = version
+	manifest.ContentHash = hash
+	manifest.SourceCommit = source
 	data, err = json.MarshalIndent(manifest, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("marshaling manifest for %s: %w", skill.Name, err)
@@ -77,9 +183,10 @@ func generateVersionedPlugin(skill config.Skill, brandRepoPath string, org confi
 	return string(data) + "\n", nil
 }
 
-// generateVersionedMarketplace generates a marketplace.json with bumped versions.
+// generateVersionedMarketplace generates a marketplace.json with each skill's
+// version from versions, keyed by skill name.
 // Returns the JSON content as a string (with trailing newline).
-func generateVersionedMarketplace(org config.Org, skills []config.Skill, brandRepoPath string) (string, error) {
+func generateVersionedMarketplace(org config.Org, skills []config.Skill, versions map[string]string) (string, error) {
 	marketplaceData, err := generator.GenerateMarketplaceJSON(org, skills)
 	if err != nil {
 		return "", fmt.Errorf("generating marketplace: %w", err)
@@ -92,8 +199,7 @@ func generateVersionedMarketplace(org config.Org, skills []config.Skill, brandRe
 	for i, plugin := range marketplace.Plugins {
 		for _, skill := range skills {
 			if plugin.Name == skill.Name || filepath.Base(plugin.Source) == skill.Name {
-				version := BumpVersion(brandRepoPath, org.Manifest.SkillsDir, skill.Name)
-				marketplace.Plugins[i].Version = version
+				marketplace.Plugins[i].Version = versions[skill.Name]
 				break
 			}
 		}
@@ -152,195 +258,361 @@ func isChaparralGenerated(path string) bool {
 	return pm.Skills == "./"
 }
 
-// WriteManifests generates and writes plugin.json for each skill and
-// marketplace.json for the org. Returns the list of written files.
-// Skills with existing non-chaparral plugin.json files are skipped.
-func WriteManifests(org config.Org, skills []config.Skill) ([]WrittenFile, error) {
-	brandRepoPath := filepath.Join(org.Path, org.BrandRepo)
-	var written []WrittenFile
-	var publishedSkills []config.Skill
+// plannedSkill is what publishing writes for one skill.
+type plannedSkill struct {
+	skill   config.Skill
+	dest    config.Skill // where the skill is published; see publishedSkill
+	version string
+	plugin  string          // plugin.json content
+	entry   *ChangelogEntry // nil when the version isn't changing
+	keep    bool            // filtered out this time: listed in marketplace.json as already published
+}
 
-	for _, skill := range skills {
-		if shouldSkipSkill(skill) {
+// planSkills works out each publishable skill's next version, plugin.json
+// and changelog entry. Skills with existing non-chaparral plugin.json files
+// are skipped, and so are internal ones. Skills left out by opts.Skills keep
+// their published version, and aren't listed at all if they've never been
+// published.
+func planSkills(org config.Org, skills []config.Skill, opts Options) ([]plannedSkill, error) {
+	selected, err := selectPublished(org, skills)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOnly(skills, selected, opts); err != nil {
+		return nil, err
+	}
+
+	var plan []plannedSkill
+	for _, skill := range selected {
+		dest := publishedSkill(org, skill)
+		if shouldSkipSkill(dest) {
+			continue
+		}
+
+		if !opts.only(skill.Name) {
+			if pm, ok := readPublished(filepath.Join(dest.Path, "plugin.json")); ok {
+				plan = append(plan, plannedSkill{skill: skill, dest: dest, version: pm.Version, keep: true})
+			}
 			continue
 		}
 
-		content, err := generateVersionedPlugin(skill, brandRepoPath, org)
+		version, err := nextVersion(skill, dest, opts.level(skill.Name))
 		if err != nil {
 			return nil, err
 		}
-
-		pluginPath := filepath.Join(skill.Path, "plugin.json")
-		_, statErr := os.Stat(pluginPath)
-		isNew := os.IsNotExist(statErr)
-
-		if err := os.WriteFile(pluginPath, []byte(content), 0644); err != nil {
-			return nil, fmt.Errorf("writing %s: %w", pluginPath, err)
+		pm, published := readPublished(filepath.Join(dest.Path, "plugin.json"))
+		source := ""
+		if dest.Path != skill.Path {
+			// A new version records where it was copied from; an unchanged
+			// one keeps what it recorded
+			source = pm.SourceCommit
+			if !published || pm.Version != version {
+				source = headCommit(skill.Path)
+			}
+		}
+		content, err := generateVersionedPlugin(skill, version, source)
+		if err != nil {
+			return nil, err
 		}
 
-		relPath, _ := filepath.Rel(brandRepoPath, pluginPath)
-		written = append(written, WrittenFile{Path: relPath, IsNew: isNew})
-		publishedSkills = append(publishedSkills, skill)
+		p := plannedSkill{skill: skill, dest: dest, version: version, plugin: content}
+		if !published || pm.Version != version {
+			if p.entry, err = newChangelogEntry(skill, dest, version, !published); err != nil {
+				return nil, err
+			}
+		}
+		plan = append(plan, p)
 	}
+	return plan, nil
+}
+
+// pendingFile is a file publishing would write or remove, by absolute path.
+type pendingFile struct {
+	path    string
+	content string
+	mode    fs.FileMode // 0 means 0644
+	remove  bool
+}
+
+// pendingFiles returns every file publishing would write for a plan: for each
+// skill, its copy in the publish target if there is one, plugin.json and
+// changelog; removals for skills the target no longer publishes; then
+// marketplace.json and the marketplace changelog.
+// Changelogs chaparral doesn't own are left out.
+func pendingFiles(org config.Org, plan []plannedSkill) ([]pendingFile, error) {
+	publishPath := org.PublishPath()
+	var files []pendingFile
+	var published []config.Skill
+	var entries []ChangelogEntry
+	versions := make(map[string]string)
+
+	for _, p := range plan {
+		if p.keep {
+			published = append(published, p.skill)
+			versions[p.skill.Name] = p.version
+			continue
+		}
+		if p.dest.Path != p.skill.Path {
+			copies, err := copyFiles(p.skill.Path, p.dest.Path)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, copies...)
+		}
+		files = append(files, pendingFile{path: filepath.Join(p.dest.Path, "plugin.json"), content: p.plugin})
+		published = append(published, p.skill)
+		versions[p.skill.Name] = p.version
+		if p.entry == nil {
+			continue
+		}
+		entries = append(entries, *p.entry)
 
-	if len(publishedSkills) > 0 {
-		content, err := generateVersionedMarketplace(org, publishedSkills, brandRepoPath)
+		path := filepath.Join(p.dest.Path, changelogFile)
+		content, ok, err := updateChangelog(path, p.entry.section())
 		if err != nil {
 			return nil, err
 		}
-
-		pluginDir := filepath.Join(brandRepoPath, ".claude-plugin")
-		if err := os.MkdirAll(pluginDir, 0755); err != nil {
-			return nil, fmt.Errorf("creating .claude-plugin: %w", err)
+		if ok {
+			files = append(files, pendingFile{path: path, content: content})
 		}
+	}
 
-		marketplacePath := filepath.Join(pluginDir, "marketplace.json")
-		_, statErr := os.Stat(marketplacePath)
-		isNew := os.IsNotExist(statErr)
+	removals, err := unpublishedFiles(org, plan)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, removals...)
 
-		if err := os.WriteFile(marketplacePath, []byte(content), 0644); err != nil {
-			return nil, fmt.Errorf("writing marketplace.json: %w", err)
+	if len(published) > 0 {
+		content, err := generateVersionedMarketplace(org, published, versions)
+		if err != nil {
+			return nil, err
 		}
+		files = append(files, pendingFile{path: filepath.Join(publishPath, ".claude-plugin", "marketplace.json"), content: content})
+	}
 
-		relPath, _ := filepath.Rel(brandRepoPath, marketplacePath)
-		written = append(written, WrittenFile{Path: relPath, IsNew: isNew})
+	if len(entries) > 0 {
+		path := filepath.Join(publishPath, ".claude-plugin", changelogFile)
+		content, ok, err := updateChangelog(path, marketplaceSection(entries))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			files = append(files, pendingFile{path: path, content: content})
+		}
 	}
 
-	return written, nil
+	return files, nil
 }
 
-// bumpVersionFromFile reads the version from an existing plugin.json file.
-func bumpVersionFromFile(path string) string {
-	data, err := os.ReadFile(path)
+// Plan is a publish worked out once: each skill's next version, plugin.json
+// and changelog entry. Previewing, listing changelog entries and writing from
+// the same Plan agree with each other, and walk each skill's git history only
+// once.
+type Plan struct {
+	org    config.Org
+	skills []plannedSkill
+}
+
+// NewPlan plans publishing skills from org; see planSkills.
+func NewPlan(org config.Org, skills []config.Skill, opts Options) (*Plan, error) {
+	planned, err := planSkills(org, skills, opts)
 	if err != nil {
-		return initialVersion
-	}
-	var pm struct {
-		Version string `json:"version"`
-	}
-	if err := json.Unmarshal(data, &pm); err != nil || pm.Version == "" {
-		return initialVersion
+		return nil, err
 	}
-	return pm.Version
+	return &Plan{org: org, skills: planned}, nil
 }
 
-// DiffManifests produces a read-only diff of what WriteManifests would do.
-// It generates the same content as WriteManifests but compares against
-// existing files on disk instead of writing.
-func DiffManifests(org config.Org, skills []config.Skill) ([]FileChange, error) {
-	brandRepoPath := filepath.Join(org.Path, org.BrandRepo)
-	var changes []FileChange
-	var publishedSkills []config.Skill
+// Write generates and writes plugin.json for each skill and marketplace.json
+// for the org, and adds a section to each changelog for skills whose version
+// changed. With a publish target, the skills are first copied there and the
+// brand repo is left untouched. Returns the list of written files. Skills
+// with existing non-chaparral plugin.json files are skipped.
+func (p *Plan) Write() ([]WrittenFile, error) {
+	publishPath := p.org.PublishPath()
+	files, err := pendingFiles(p.org, p.skills)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, skill := range skills {
-		if shouldSkipSkill(skill) {
+	var written []WrittenFile
+	for _, f := range files {
+		relPath, _ := filepath.Rel(publishPath, f.path)
+		if f.remove {
+			if err := os.Remove(f.path); err != nil {
+				return nil, fmt.Errorf("removing %s: %w", f.path, err)
+			}
+			removeEmptyDirs(filepath.Dir(f.path), publishPath)
+			written = append(written, WrittenFile{Path: relPath, Removed: true})
 			continue
 		}
 
-		content, err := generateVersionedPlugin(skill, brandRepoPath, org)
-		if err != nil {
-			return nil, err
+		if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+			return nil, fmt.Errorf("creating %s: %w", filepath.Dir(f.path), err)
 		}
 
-		pluginPath := filepath.Join(skill.Path, "plugin.json")
-		relPath, _ := filepath.Rel(brandRepoPath, pluginPath)
+		_, statErr := os.Stat(f.path)
+		isNew := os.IsNotExist(statErr)
 
-		change, err := diffFile(pluginPath, relPath, content)
-		if err != nil {
-			return nil, err
+		mode := f.mode
+		if mode == 0 {
+			mode = 0644
+		}
+		if err := os.WriteFile(f.path, []byte(f.content), mode); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", f.path, err)
+		}
+		if err := os.Chmod(f.path, mode); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", f.path, err)
 		}
 
-		changes = append(changes, change)
-		publishedSkills = append(publishedSkills, skill)
+		written = append(written, WrittenFile{Path: relPath, IsNew: isNew})
 	}
 
-	if len(publishedSkills) > 0 {
-		content, err := generateVersionedMarketplace(org, publishedSkills, brandRepoPath)
-		if err != nil {
-			return nil, err
+	return written, nil
+}
+
+// removeEmptyDirs removes dir and each parent it leaves empty, stopping at
+// root, so removed skills and folders don't linger in the publish target.
+func removeEmptyDirs(dir, root string) {
+	for dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)) {
+		if os.Remove(dir) != nil {
+			return // not empty
 		}
+		dir = filepath.Dir(dir)
+	}
+}
 
-		marketplacePath := filepath.Join(brandRepoPath, ".claude-plugin", "marketplace.json")
-		relPath, _ := filepath.Rel(brandRepoPath, marketplacePath)
+// Diff produces a read-only diff of what Write would do. It generates the
+// same content but compares against existing files on disk instead of
+// writing.
+func (p *Plan) Diff() ([]FileChange, error) {
+	publishPath := p.org.PublishPath()
+	files, err := pendingFiles(p.org, p.skills)
+	if err != nil {
+		return nil, err
+	}
 
-		change, err := diffFile(marketplacePath, relPath, content)
+	var changes []FileChange
+	for _, f := range files {
+		relPath, _ := filepath.Rel(publishPath, f.path)
+		if f.remove {
+			changes = append(changes, FileChange{Path: relPath, Kind: "removed"})
+			continue
+		}
+		change, err := diffFile(f.path, relPath, f.content)
 		if err != nil {
 			return nil, err
 		}
-
 		changes = append(changes, change)
 	}
 
 	return changes, nil
 }
 
-// CheckFreshness reports whether each skill's source files are newer than
-// its published plugin.json. When mtimes are within 1 second (post-clone
-// scenario), falls back to content comparison.
+// Changelog returns the changelog entries Write would add: one for each
+// skill whose version is changing, and so one for each release CommitAndPush
+// should tag.
+func (p *Plan) Changelog() []ChangelogEntry {
+	var entries []ChangelogEntry
+	for _, ps := range p.skills {
+		if ps.entry != nil {
+			entries = append(entries, *ps.entry)
+		}
+	}
+	return entries
+}
+
+// WriteManifests plans and writes a publish in one go; see Plan.Write.
+func WriteManifests(org config.Org, skills []config.Skill, opts Options) ([]WrittenFile, error) {
+	plan, err := NewPlan(org, skills, opts)
+	if err != nil {
+		return nil, err
+	}
+	return plan.Write()
+}
+
+// DiffManifests produces a read-only diff of what WriteManifests would do;
+// see Plan.Diff.
+func DiffManifests(org config.Org, skills []config.Skill, opts Options) ([]FileChange, error) {
+	plan, err := NewPlan(org, skills, opts)
+	if err != nil {
+		return nil, err
+	}
+	return plan.Diff()
+}
+
+// PendingChangelog returns the changelog entries WriteManifests would add;
+// see Plan.Changelog.
+func PendingChangelog(org config.Org, skills []config.Skill, opts Options) ([]ChangelogEntry, error) {
+	plan, err := NewPlan(org, skills, opts)
+	if err != nil {
+		return nil, err
+	}
+	return plan.Changelog(), nil
+}
+
+// CheckFreshness reports whether each skill's content has changed since it
+// was last published, by comparing content hashes. The skill's newest release
+// tag in HEAD's history is the authoritative published state; skills without
+// one fall back to their plugin.json. With a publish target, both are read
+// from the target repo. A published version without a recorded hash counts
+// as stale.
 func CheckFreshness(org config.Org, skills []config.Skill) ([]FreshnessResult, error) {
-	brandRepoPath := filepath.Join(org.Path, org.BrandRepo)
-	results := make([]FreshnessResult, 0, len(skills))
+	published, err := selectPublished(org, skills)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]FreshnessResult, 0, len(published))
 
-	for _, skill := range skills {
-		pluginPath := filepath.Join(skill.Path, "plugin.json")
+	for _, skill := range published {
+		dest := publishedSkill(org, skill)
+		pluginPath := filepath.Join(dest.Path, "plugin.json")
 		result := FreshnessResult{Skill: skill.Name}
 
-		pluginInfo, err := os.Stat(pluginPath)
-		if os.IsNotExist(err) {
-			// Never published
+		hash, err := ContentHash(skill.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := os.Stat(dest.Path); os.IsNotExist(err) {
+			// Never copied to the publish target
 			result.Stale = true
 			results = append(results, result)
 			continue
-		} else if err != nil {
-			return nil, fmt.Errorf("checking %s: %w", pluginPath, err)
 		}
-
-		// Read published version
-		result.PublishedVersion = bumpVersionFromFile(pluginPath)
-		pluginMtime := pluginInfo.ModTime()
-
-		// Check all files in the skill directory
-		entries, err := os.ReadDir(skill.Path)
+		repo, rel, inRepo, err := openSkillRepo(dest.Path)
 		if err != nil {
-			return nil, fmt.Errorf("reading skill dir %s: %w", skill.Path, err)
+			return nil, err
 		}
-
-		stale := false
-		for _, entry := range entries {
-			if entry.Name() == "plugin.json" {
-				continue
-			}
-			info, err := entry.Info()
+		if inRepo {
+			release, commit, tagged, err := reachableTag(repo, skill.Name)
 			if err != nil {
+				return nil, err
+			}
+			if tagged {
+				recorded := taggedHash(commit, rel)
+				result.PublishedVersion = release.Version
+				result.Stale = recorded == "" || recorded != hash
+				results = append(results, result)
 				continue
 			}
+		}
 
-			diff := info.ModTime().Sub(pluginMtime)
-			if diff > time.Second {
-				// Skill file is clearly newer
-				stale = true
-				break
-			} else if diff > -time.Second {
-				// Mtimes within 1 second — fall back to content comparison
-				expected, genErr := generateVersionedPlugin(skill, brandRepoPath, org)
-				if genErr != nil {
-					stale = true
-					break
-				}
-				existing, readErr := os.ReadFile(pluginPath)
-				if readErr != nil {
-					stale = true
-					break
-				}
-				if string(existing) != expected {
-					stale = true
-				}
-				break
-			}
-			// Skill file is older — not stale from this file
+		if _, err := os.Stat(pluginPath); os.IsNotExist(err) {
+			// Never published
+			result.Stale = true
+			results = append(results, result)
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("checking %s: %w", pluginPath, err)
 		}
 
-		result.Stale = stale
+		pm, ok := readPublished(pluginPath)
+		result.PublishedVersion = initialVersion
+		if ok {
+			result.PublishedVersion = pm.Version
+		}
+		result.Stale = pm.ContentHash == "" || pm.ContentHash != hash
 		results = append(results, result)
 	}
 
@@ -0,0 +1,150 @@
+package publisher
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Level is how much of a version to bump.
+type Level string
+
+const (
+	BumpPatch Level = "patch"
+	BumpMinor Level = "minor"
+	BumpMajor Level = "major"
+	BumpAuto  Level = "auto" // inferred from conventional commits; see InferBump
+)
+
+// ParseLevel parses "major", "minor", "patch" or "auto".
+func ParseLevel(s string) (Level, error) {
+	switch l := Level(strings.ToLower(strings.TrimSpace(s))); l {
+	case BumpPatch, BumpMinor, BumpMajor, BumpAuto:
+		return l, nil
+	}
+	return "", fmt.Errorf("unknown bump level %q (want major, minor, patch or auto)", s)
+}
+
+// Version is a semantic version with an optional pre-release tag, as in
+// "1.2.0" or "1.2.0-rc.1".
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 string // pre-release identifiers, without the leading "-"
+}
+
+// ParseVersion parses a semantic version. A leading "v" is allowed; build
+// metadata ("+...") isn't.
+func ParseVersion(s string) (Version, error) {
+	text := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	core, pre, hasPre := strings.Cut(text, "-")
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q: want major.minor.patch", s)
+	}
+
+	var nums [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || (len(p) > 1 && p[0] == '0') {
+			return Version{}, fmt.Errorf("invalid version %q: %q isn't a version number", s, p)
+		}
+		nums[i] = n
+	}
+
+	if hasPre {
+		for _, id := range strings.Split(pre, ".") {
+			if id == "" || strings.Trim(id, "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ-") != "" {
+				return Version{}, fmt.Errorf("invalid version %q: bad pre-release tag %q", s, pre)
+			}
+		}
+	}
+	return Version{Major: nums[0], Minor: nums[1], Patch: nums[2], Pre: pre}, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Bump returns the next version at the given level. A pre-release bumps to
+// its own release when that satisfies the level: 1.2.0-rc.1 goes to 1.2.0
+// for a patch or minor bump, but to 2.0.0 for a major one.
+func (v Version) Bump(level Level) Version {
+	if v.Pre != "" {
+		release := Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+		switch {
+		case level == BumpPatch,
+			level == BumpMinor && v.Patch == 0,
+			level == BumpMajor && v.Minor == 0 && v.Patch == 0:
+			return release
+		}
+		v = release
+	}
+
+	switch level {
+	case BumpMajor:
+		return Version{Major: v.Major + 1}
+	case BumpMinor:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	default:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+}
+
+// Compare returns -1, 0 or 1 as v is older than, the same as, or newer than
+// other. A pre-release is older than its release.
+func (v Version) Compare(other Version) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	switch {
+	case v.Pre == other.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case other.Pre == "":
+		return -1
+	}
+	return comparePre(v.Pre, other.Pre)
+}
+
+// comparePre orders pre-release tags identifier by identifier: numeric ones
+// numerically and below alphanumeric ones, and a shorter tag first when one
+// is a prefix of the other.
+func comparePre(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,66 @@
+package publisher
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"v0.1.0", "0.1.0", true},
+		{"1.2.0-rc.1", "1.2.0-rc.1", true},
+		{"1.2", "", false},
+		{"1.02.0", "", false},
+		{"1.2.0-", "", false},
+		{"1.2.0-rc..1", "", false},
+	}
+	for _, tt := range tests {
+		v, err := ParseVersion(tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("ParseVersion(%q) error = %v, want ok=%v", tt.in, err, tt.ok)
+			continue
+		}
+		if tt.ok && v.String() != tt.want {
+			t.Errorf("ParseVersion(%q) = %s, want %s", tt.in, v, tt.want)
+		}
+	}
+}
+
+func TestVersionBump(t *testing.T) {
+	tests := []struct {
+		from  string
+		level Level
+		want  string
+	}{
+		{"1.2.3", BumpPatch, "1.2.4"},
+		{"1.2.3", BumpMinor, "1.3.0"},
+		{"1.2.3", BumpMajor, "2.0.0"},
+		{"1.2.0-rc.1", BumpPatch, "1.2.0"},
+		{"1.2.0-rc.1", BumpMinor, "1.2.0"},
+		{"1.2.0-rc.1", BumpMajor, "2.0.0"},
+		{"2.0.0-beta", BumpMajor, "2.0.0"},
+		{"1.2.1-rc.1", BumpMinor, "1.3.0"},
+	}
+	for _, tt := range tests {
+		v, err := ParseVersion(tt.from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := v.Bump(tt.level).String(); got != tt.want {
+			t.Errorf("%s bumped %s = %s, want %s", tt.from, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-beta", "1.0.0-rc.2", "1.0.0-rc.10", "1.0.0", "1.0.1", "1.1.0", "2.0.0"}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, _ := ParseVersion(ordered[i])
+		b, _ := ParseVersion(ordered[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("expected %s < %s", a, b)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package publisher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// Release is a skill published at a version.
+type Release struct {
+	Skill   string
+	Version string
+}
+
+// Tag is the name of the annotated tag marking the release, as in
+// "brand-voice@v0.3.0".
+func (r Release) Tag() string {
+	return r.Skill + "@v" + r.Version
+}
+
+// PendingTag is a release that was committed but never tagged, and the
+// commit its tag belongs on: one published through a pull request that has
+// since been merged, or one whose push failed.
+type PendingTag struct {
+	Release
+	Commit string
+}
+
+// PendingTags returns the releases the publish repo has committed at HEAD but
+// not tagged: for each planned skill, the version in its committed
+// plugin.json if there's no tag for it, to be tagged at the last commit that
+// changed that plugin.json. Publishing through a pull request leaves its
+// releases untagged until they're merged, and this is how they're found.
+func (p *Plan) PendingTags() ([]PendingTag, error) {
+	var tags []PendingTag
+	for _, ps := range p.skills {
+		repo, rel, ok, err := openSkillRepo(ps.dest.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		tag, ok, err := pendingTag(repo, rel, ps.skill.Name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ps.skill.Name, err)
+		}
+		if ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// pendingTag finds the untagged release recorded in the skill's plugin.json
+// at HEAD, if there is one.
+func pendingTag(repo *git.Repository, rel, skill string) (PendingTag, bool, error) {
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return PendingTag{}, false, nil
+	} else if err != nil {
+		return PendingTag{}, false, fmt.Errorf("reading HEAD: %w", err)
+	}
+	tip, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return PendingTag{}, false, fmt.Errorf("reading HEAD: %w", err)
+	}
+	pm, ok := committedPlugin(tip, rel)
+	if !ok {
+		return PendingTag{}, false, nil
+	}
+	release := Release{Skill: skill, Version: pm.Version}
+	if _, err := repo.Tag(release.Tag()); err == nil {
+		return PendingTag{}, false, nil
+	}
+
+	var last *object.Commit
+	pluginPath := rel + "/plugin.json"
+	_, err = skillLog(repo, rel, func(c, parent *object.Commit) bool {
+		if entryHash(parent, pluginPath) != entryHash(c, pluginPath) {
+			last = c
+			return true
+		}
+		return false
+	})
+	if err != nil || last == nil {
+		return PendingTag{}, false, err
+	}
+	return PendingTag{Release: release, Commit: last.Hash.String()}, true, nil
+}
+
+// publishedTag finds the newest "<skill>@v<version>" tag in repo and the
+// commit it points at. ok is false if the skill has never been tagged.
+func publishedTag(repo *git.Repository, skill string) (Release, *object.Commit, bool, error) {
+	refs, err := repo.Tags()
+	if err != nil {
+		return Release{}, nil, false, fmt.Errorf("listing tags: %w", err)
+	}
+
+	prefix := skill + "@v"
+	var best Version
+	var bestRef *plumbing.Reference
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+		v, err := ParseVersion(strings.TrimPrefix(name, prefix))
+		if err != nil {
+			return nil // not one of ours
+		}
+		if bestRef == nil || v.Compare(best) > 0 {
+			best, bestRef = v, ref
+		}
+		return nil
+	})
+	if err != nil {
+		return Release{}, nil, false, fmt.Errorf("listing tags: %w", err)
+	}
+	if bestRef == nil {
+		return Release{}, nil, false, nil
+	}
+
+	commit, err := tagCommit(repo, bestRef)
+	if err != nil {
+		return Release{}, nil, false, fmt.Errorf("resolving tag %s: %w", bestRef.Name().Short(), err)
+	}
+	return Release{Skill: skill, Version: best.String()}, commit, true, nil
+}
+
+// reachableTag is publishedTag for tags in HEAD's history. A newest tag on a
+// commit HEAD can't reach — one on another branch, or on a branch that was
+// squashed or rebased when it merged — doesn't mark what the checked-out
+// branch has published, so ok is false and callers fall back to plugin.json.
+func reachableTag(repo *git.Repository, skill string) (Release, *object.Commit, bool, error) {
+	release, tagged, ok, err := publishedTag(repo, skill)
+	if err != nil || !ok {
+		return Release{}, nil, false, err
+	}
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return Release{}, nil, false, nil
+	} else if err != nil {
+		return Release{}, nil, false, fmt.Errorf("reading HEAD: %w", err)
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return Release{}, nil, false, fmt.Errorf("reading HEAD: %w", err)
+	}
+	reachable, err := tagged.IsAncestor(headCommit)
+	if err != nil {
+		return Release{}, nil, false, fmt.Errorf("checking tag %s: %w", release.Tag(), err)
+	}
+	if !reachable {
+		return Release{}, nil, false, nil
+	}
+	return release, tagged, true, nil
+}
+
+// tagCommit resolves an annotated or lightweight tag to its commit.
+func tagCommit(repo *git.Repository, ref *plumbing.Reference) (*object.Commit, error) {
+	tag, err := repo.TagObject(ref.Hash())
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return repo.CommitObject(ref.Hash())
+	} else if err != nil {
+		return nil, err
+	}
+	return tag.Commit()
+}
+
+// taggedHash returns the content hash recorded in a skill's plugin.json at
+// the commit a tag points at, or "" if there isn't one.
+func taggedHash(commit *object.Commit, rel string) string {
+	pm, _ := committedPlugin(commit, rel)
+	return pm.ContentHash
+}
+
+// committedPlugin reads a skill's plugin.json as of commit. ok is false if
+// it's missing or unparseable there.
+func committedPlugin(commit *object.Commit, rel string) (publishedPlugin, bool) {
+	file, err := commit.File(rel + "/plugin.json")
+	if err != nil {
+		return publishedPlugin{}, false
+	}
+	contents, err := file.Contents()
+	if err != nil {
+		return publishedPlugin{}, false
+	}
+	var pm publishedPlugin
+	if err := json.Unmarshal([]byte(contents), &pm); err != nil || pm.Version == "" {
+		return publishedPlugin{}, false
+	}
+	return pm, true
+}
+
+// openSkillRepo opens the git repo containing skillPath and returns the
+// skill's path relative to the repo root. ok is false if it isn't in one.
+func openSkillRepo(skillPath string) (*git.Repository, string, bool, error) {
+	repo, rel, err := openRepoAt(skillPath)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil, "", false, nil
+	} else if err != nil {
+		return nil, "", false, err
+	}
+	return repo, rel, true, nil
+}
+
+// publishedAt returns when a skill was last published from dest: the commit
+// time of its newest release tag in HEAD's history or, failing that, of the last
+// commit that changed its plugin.json. Returns the zero time if it has never
+// been published, or dest isn't in a git repo.
+func publishedAt(dest config.Skill) (time.Time, error) {
+	if _, err := os.Stat(dest.Path); os.IsNotExist(err) {
+		return time.Time{}, nil
+	}
+	repo, rel, ok, err := openSkillRepo(dest.Path)
+	if err != nil || !ok {
+		return time.Time{}, err
+	}
+
+	_, tagged, hasTag, err := reachableTag(repo, dest.Name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if hasTag {
+		return tagged.Committer.When, nil
+	}
+
+	var last *object.Commit
+	pluginPath := rel + "/plugin.json"
+	_, err = skillLog(repo, rel, func(c, parent *object.Commit) bool {
+		if entryHash(parent, pluginPath) != entryHash(c, pluginPath) {
+			last = c
+			return true
+		}
+		return false
+	})
+	if err != nil || last == nil {
+		return time.Time{}, err
+	}
+	return last.Committer.When, nil
+}
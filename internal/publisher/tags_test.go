@@ -0,0 +1,183 @@
+package publisher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// tagHead creates an annotated tag at HEAD.
+func tagHead(t *testing.T, repo *git.Repository, name string) {
+	t.Helper()
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.CreateTag(name, head.Hash(), &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "test", Email: "test@test.com", When: time.Now()},
+		Message: name,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// openTestRepo opens the git repo at dir.
+func openTestRepo(t *testing.T, dir string) *git.Repository {
+	t.Helper()
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestCheckFreshness_Tagged(t *testing.T) {
+	dir := initTestRepo(t)
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, repo, dir, "skills/voice/SKILL.md", "---\nname: voice\ndescription: d\n---\n", "feat(voice): add skill")
+	skill := config.Skill{Name: "voice", Path: filepath.Join(dir, "skills", "voice")}
+
+	hash, err := ContentHash(skill.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, repo, dir, "skills/voice/plugin.json", `{"name":"voice","version":"0.2.0","skills":"./","contentHash":"`+hash+`"}`, "publish voice@v0.2.0")
+	tagHead(t, repo, "voice@v0.2.0")
+	tagHead(t, repo, "voice@v0.10.0-rc.1")
+	tagHead(t, repo, "voice@v0.9.0")
+
+	// An uncommitted plugin.json doesn't change what was published
+	pluginPath := filepath.Join(skill.Path, "plugin.json")
+	if err := os.WriteFile(pluginPath, []byte(`{"name":"voice","version":"0.11.0","skills":"./"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := CheckFreshness(config.Org{}, []config.Skill{skill})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Stale {
+		t.Error("expected stale=false when content matches the tagged publish")
+	}
+	if results[0].PublishedVersion != "0.10.0-rc.1" {
+		t.Errorf("expected newest tag 0.10.0-rc.1, got %s", results[0].PublishedVersion)
+	}
+
+	commitFile(t, repo, dir, "skills/voice/SKILL.md", "---\nname: voice\ndescription: d\n---\nEdited.\n", "fix(voice): edit")
+	results, err = CheckFreshness(config.Org{}, []config.Skill{skill})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].Stale {
+		t.Error("expected stale=true after an edit since the tag")
+	}
+}
+
+func TestSkillCommits_SinceTag(t *testing.T) {
+	dir := initTestRepo(t)
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, repo, dir, "skills/voice/SKILL.md", "---\nname: voice\n---\n", "feat(voice): add skill")
+	tagHead(t, repo, "voice@v0.1.0")
+	// A plugin.json change after the tag isn't a publish boundary any more
+	commitFile(t, repo, dir, "skills/voice/plugin.json", `{"version":"0.1.0"}`, "chore: write manifests")
+	commitFile(t, repo, dir, "skills/voice/SKILL.md", "---\nname: voice\n---\nMore.\n", "feat(voice): more")
+
+	commits, err := SkillCommits(filepath.Join(dir, "skills", "voice"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("expected 2 commits since the tag, got %+v", commits)
+	}
+}
+
+func TestSkillCommits_TagOnSideBranch(t *testing.T) {
+	dir := initTestRepo(t)
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, repo, dir, "skills/voice/SKILL.md", "---\nname: voice\n---\n", "feat(voice): add skill")
+	commitFile(t, repo, dir, "skills/voice/plugin.json", `{"version":"0.1.0"}`, "chore: publish voice")
+
+	// A release tagged on a branch that was squashed rather than merged
+	gitT(t, dir, "checkout", "--quiet", "-b", "publish")
+	commitFile(t, repo, dir, "skills/voice/plugin.json", `{"version":"0.2.0"}`, "chore: publish voice")
+	tagHead(t, repo, "voice@v0.2.0")
+	gitT(t, dir, "checkout", "--quiet", "master")
+
+	commitFile(t, repo, dir, "skills/voice/SKILL.md", "---\nname: voice\n---\nMore.\n", "fix(voice): more")
+
+	commits, err := SkillCommits(filepath.Join(dir, "skills", "voice"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The unreachable tag is ignored, so the walk stops at plugin.json
+	if len(commits) != 1 || commits[0].Subject != "fix(voice): more" {
+		t.Errorf("expected only the commit since the last plugin.json change, got %+v", commits)
+	}
+}
+
+func TestPendingTags_MergedRelease(t *testing.T) {
+	org, skills, remoteDir := setupPreflightOrg(t)
+	brandPath := filepath.Join(org.Path, org.BrandRepo)
+
+	// A publish PR, merged and pulled
+	if _, err := WriteManifests(org, skills, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	gitT(t, brandPath, "add", "-A")
+	gitT(t, brandPath, "commit", "--quiet", "-m", "publish test-skill@v0.1.0")
+	gitT(t, brandPath, "push", "--quiet", "origin", "main")
+	merged := headCommit(brandPath)
+	commitFile(t, openTestRepo(t, brandPath), brandPath, "README.md", "# brand\n", "docs: readme")
+
+	plan, err := NewPlan(org, skills, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending, err := plan.PendingTags()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Tag() != "test-skill@v0.1.0" || pending[0].Commit != merged {
+		t.Fatalf("expected test-skill@v0.1.0 pending at the merged commit, got %+v", pending)
+	}
+
+	if err := PushTags(brandPath, pending, config.PublishConfig{}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	remote := openTestRepo(t, remoteDir)
+	ref, err := remote.Tag("test-skill@v0.1.0")
+	if err != nil {
+		t.Fatalf("expected tag on remote: %v", err)
+	}
+	tagged, err := tagCommit(remote, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tagged.Hash.String() != merged {
+		t.Errorf("tag points at %s, want %s", tagged.Hash, merged)
+	}
+
+	pending, err = plan.PendingTags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected nothing pending once tagged, got %+v", pending)
+	}
+}
@@ -0,0 +1,207 @@
+package publisher
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+	"github.com/manzanita-research/chaparral/internal/generator"
+)
+
+// selectPublished filters skills to the ones that go to the marketplace: those
+// the manifest's publish.skills lists, or all of them if it lists none, less
+// the internal ones.
+func selectPublished(org config.Org, skills []config.Skill) ([]config.Skill, error) {
+	wanted := make(map[string]bool)
+	for _, name := range org.Manifest.Publish.Skills {
+		wanted[name] = true
+	}
+	var selected []config.Skill
+	for _, skill := range skills {
+		if org.Manifest.Publish.Skills != nil && !wanted[skill.Name] {
+			continue
+		}
+		internal, err := generator.SkillIsInternal(org, skill)
+		if err != nil {
+			return nil, err
+		}
+		if !internal {
+			selected = append(selected, skill)
+		}
+	}
+	return selected, nil
+}
+
+// checkOnly refuses a --skill filter that names a skill which isn't published.
+func checkOnly(skills, selected []config.Skill, opts Options) error {
+	published := make(map[string]bool)
+	for _, skill := range selected {
+		published[skill.Name] = true
+	}
+	for _, skill := range skills {
+		if opts.Skills != nil && opts.only(skill.Name) && !published[skill.Name] {
+			return fmt.Errorf("%s is internal or not in publish.skills, so it can't be published", skill.Name)
+		}
+	}
+	return nil
+}
+
+// publishedSkill returns where a skill is published: its own directory when
+// the org publishes from the brand repo, or its copy under the same skills
+// directory in the publish target.
+func publishedSkill(org config.Org, skill config.Skill) config.Skill {
+	if !org.PublishesSeparately() {
+		return skill
+	}
+	return config.Skill{
+		Name: skill.Name,
+		Path: filepath.Join(org.PublishPath(), org.Manifest.SkillsDir, skill.Name),
+	}
+}
+
+// unpublishedFiles returns a removal for every file of each skill left in
+// the publish target that's no longer published — one marked internal, or
+// dropped from publish.skills or the brand repo. Only directories under the
+// target's skills directory with a plugin.json chaparral generated count;
+// anything else there was put there by hand.
+func unpublishedFiles(org config.Org, plan []plannedSkill) ([]pendingFile, error) {
+	if !org.PublishesSeparately() {
+		return nil, nil
+	}
+	dir := filepath.Join(org.PublishPath(), org.Manifest.SkillsDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+	planned := make(map[string]bool)
+	for _, p := range plan {
+		planned[p.dest.Path] = true
+	}
+
+	var files []pendingFile
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		if !e.IsDir() || planned[path] || !isChaparralGenerated(filepath.Join(path, "plugin.json")) {
+			continue
+		}
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				files = append(files, pendingFile{path: p, remove: true})
+			}
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+	return files, nil
+}
+
+// headCommit returns the commit checked out in the git repo containing path,
+// or "" if it isn't in one or has no commits yet.
+func headCommit(path string) string {
+	repo, _, err := openRepoAt(path)
+	if err != nil {
+		return ""
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+	return head.Hash().String()
+}
+
+// copyFiles returns the files that make dest a copy of src: every file in src
+// whose content differs in dest, and a removal for every file only in dest.
+// Dotfiles are skipped, and so are the files chaparral generates in dest.
+func copyFiles(src, dest string) ([]pendingFile, error) {
+	srcFiles, err := skillFiles(src)
+	if err != nil {
+		return nil, err
+	}
+	destFiles, err := skillFiles(dest)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var files []pendingFile
+	for _, rel := range sortedKeys(srcFiles) {
+		f := srcFiles[rel]
+		if d, ok := destFiles[rel]; ok && bytes.Equal(f.data, d.data) && f.mode == d.mode {
+			continue
+		}
+		files = append(files, pendingFile{path: filepath.Join(dest, filepath.FromSlash(rel)), content: string(f.data), mode: f.mode})
+	}
+	for _, rel := range sortedKeys(destFiles) {
+		if _, ok := srcFiles[rel]; !ok {
+			files = append(files, pendingFile{path: filepath.Join(dest, filepath.FromSlash(rel)), remove: true})
+		}
+	}
+	return files, nil
+}
+
+// copiedFile is a file's content and permissions.
+type copiedFile struct {
+	data []byte
+	mode fs.FileMode
+}
+
+// skillFiles reads every file in a skill directory by slash-separated
+// relative path, leaving out dotfiles and generated files.
+func skillFiles(dir string) (map[string]copiedFile, error) {
+	files := make(map[string]copiedFile)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && d.Name()[0] == '.' {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if hashExclude[rel] || isGeneratedChangelog(dir, rel) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[rel] = copiedFile{data: data, mode: info.Mode().Perm()}
+		return nil
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+func sortedKeys(m map[string]copiedFile) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
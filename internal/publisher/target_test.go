@@ -0,0 +1,261 @@
+package publisher
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// setupTargetOrg creates an org whose skills publish to a separate
+// "marketplace" directory next to the brand repo.
+func setupTargetOrg(t *testing.T) (config.Org, []config.Skill) {
+	t.Helper()
+	org, skills := setupOrg(t)
+	if err := os.MkdirAll(filepath.Join(org.Path, "marketplace"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	org.Manifest.Publish.Target = "marketplace"
+	return org, skills
+}
+
+func TestWriteManifests_PublishTarget(t *testing.T) {
+	org, skills := setupTargetOrg(t)
+	script := filepath.Join(skills[0].Path, "scripts", "run.sh")
+	if err := os.MkdirAll(filepath.Dir(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := WriteManifests(org, skills, Options{}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	brandPath := filepath.Join(org.Path, org.BrandRepo)
+	if _, err := os.Stat(filepath.Join(skills[0].Path, "plugin.json")); !os.IsNotExist(err) {
+		t.Error("expected no plugin.json in the brand repo")
+	}
+	if _, err := os.Stat(filepath.Join(brandPath, ".claude-plugin")); !os.IsNotExist(err) {
+		t.Error("expected no .claude-plugin in the brand repo")
+	}
+
+	targetSkill := filepath.Join(org.PublishPath(), org.Manifest.SkillsDir, "test-skill")
+	for _, rel := range []string{"SKILL.md", "plugin.json", "CHANGELOG.md"} {
+		if _, err := os.Stat(filepath.Join(targetSkill, rel)); err != nil {
+			t.Errorf("expected %s in the target: %v", rel, err)
+		}
+	}
+	info, err := os.Stat(filepath.Join(targetSkill, "scripts", "run.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Error("expected the copied script to stay executable")
+	}
+	if _, err := os.Stat(filepath.Join(org.PublishPath(), ".claude-plugin", "marketplace.json")); err != nil {
+		t.Errorf("expected marketplace.json in the target: %v", err)
+	}
+
+	results, err := CheckFreshness(org, skills)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Stale || results[0].PublishedVersion != "0.1.0" {
+		t.Errorf("expected fresh 0.1.0 after publishing, got %+v", results[0])
+	}
+
+	// Deleting a file in the brand repo removes the copy
+	if err := os.Remove(script); err != nil {
+		t.Fatal(err)
+	}
+	written, err := WriteManifests(org, skills, Options{})
+	if err != nil {
+		t.Fatalf("second write failed: %v", err)
+	}
+	removed := false
+	for _, w := range written {
+		removed = removed || (w.Removed && filepath.Base(w.Path) == "run.sh")
+	}
+	if !removed {
+		t.Errorf("expected run.sh to be removed, got %+v", written)
+	}
+	if _, err := os.Stat(filepath.Join(targetSkill, "scripts", "run.sh")); !os.IsNotExist(err) {
+		t.Error("expected run.sh to be gone from the target")
+	}
+	pm, _ := readPublished(filepath.Join(targetSkill, "plugin.json"))
+	if pm.Version != "0.1.1" {
+		t.Errorf("expected 0.1.1 after the removal, got %s", pm.Version)
+	}
+}
+
+func TestWriteManifests_PublishSkills(t *testing.T) {
+	org, skills := setupTargetOrg(t)
+	other := setupSkillDir(t, org.SkillsPath(), "internal-notes")
+	skills = append(skills, other)
+	org.Manifest.Publish.Skills = []string{"test-skill"}
+
+	if _, err := WriteManifests(org, skills, Options{}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(org.PublishPath(), org.Manifest.SkillsDir, "internal-notes")); !os.IsNotExist(err) {
+		t.Error("expected an unlisted skill not to be copied")
+	}
+
+	results, err := CheckFreshness(org, skills)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Skill != "test-skill" {
+		t.Errorf("expected only test-skill to be checked, got %+v", results)
+	}
+}
+
+func TestWriteManifests_InternalSkill(t *testing.T) {
+	org, skills := setupOrg(t)
+	private := setupSkillDir(t, org.SkillsPath(), "client-notes")
+	md := "---\nname: client-notes\ndescription: Acme account notes\npublish: false\n---\n"
+	if err := os.WriteFile(filepath.Join(private.Path, "SKILL.md"), []byte(md), 0644); err != nil {
+		t.Fatal(err)
+	}
+	skills = append(skills, private)
+
+	if _, err := WriteManifests(org, skills, Options{}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(private.Path, "plugin.json")); !os.IsNotExist(err) {
+		t.Error("expected no plugin.json for an internal skill")
+	}
+	data, err := os.ReadFile(filepath.Join(org.PublishPath(), ".claude-plugin", "marketplace.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "client-notes") {
+		t.Errorf("internal skill leaked into marketplace.json:\n%s", data)
+	}
+
+	_, err = WriteManifests(org, skills, Options{Skills: []string{"client-notes"}})
+	if err == nil || !strings.Contains(err.Error(), "can't be published") {
+		t.Errorf("expected --skill on an internal skill to fail, got %v", err)
+	}
+}
+
+func TestWriteManifests_SkillFilter(t *testing.T) {
+	org, skills := setupOrg(t)
+	second := setupSkillDir(t, org.SkillsPath(), "second-skill")
+	skills = append(skills, second)
+	if _, err := WriteManifests(org, skills, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, skill := range skills {
+		extra := filepath.Join(skill.Path, "examples.md")
+		if err := os.WriteFile(extra, []byte("# Examples\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := WriteManifests(org, skills, Options{Skills: []string{"second-skill"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	first, _ := readPublished(filepath.Join(skills[0].Path, "plugin.json"))
+	if first.Version != "0.1.0" {
+		t.Errorf("expected the filtered-out skill to stay at 0.1.0, got %s", first.Version)
+	}
+	bumped, _ := readPublished(filepath.Join(second.Path, "plugin.json"))
+	if bumped.Version != "0.1.1" {
+		t.Errorf("expected second-skill at 0.1.1, got %s", bumped.Version)
+	}
+
+	data, err := os.ReadFile(filepath.Join(org.PublishPath(), ".claude-plugin", "marketplace.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"test-skill"`) {
+		t.Errorf("expected the filtered-out skill to stay in marketplace.json:\n%s", data)
+	}
+}
+
+func TestPendingChangelog_PublishTargetSinceSourceCommit(t *testing.T) {
+	org, skills := setupTargetOrg(t)
+	brandPath := filepath.Join(org.Path, org.BrandRepo)
+	gitT(t, brandPath, "init", "--quiet", "-b", "main")
+	gitT(t, brandPath, "add", "-A")
+	gitT(t, brandPath, "commit", "--quiet", "-m", "feat: add test-skill")
+
+	if _, err := WriteManifests(org, skills, Options{}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	targetSkill := filepath.Join(org.PublishPath(), org.Manifest.SkillsDir, "test-skill")
+	pm, _ := readPublished(filepath.Join(targetSkill, "plugin.json"))
+	if pm.SourceCommit != headCommit(brandPath) {
+		t.Errorf("sourceCommit = %q, want the brand repo's HEAD", pm.SourceCommit)
+	}
+
+	// The target's commit times say nothing about the brand repo's history
+	skillMD := filepath.Join(skills[0].Path, "SKILL.md")
+	data, err := os.ReadFile(skillMD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(skillMD, append(data, "\nMore.\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitT(t, brandPath, "commit", "--quiet", "-am", "fix: more")
+
+	entries, err := PendingChangelog(org, skills, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || len(entries[0].Commits) != 1 || entries[0].Commits[0].Subject != "fix: more" {
+		t.Errorf("expected one entry with the commit since the copy, got %+v", entries)
+	}
+}
+
+func TestWriteManifests_PublishTargetRemovesUnpublished(t *testing.T) {
+	org, skills := setupTargetOrg(t)
+	second := setupSkillDir(t, org.SkillsPath(), "second-skill")
+	skills = append(skills, second)
+	if _, err := WriteManifests(org, skills, Options{}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	targetSkills := filepath.Join(org.PublishPath(), org.Manifest.SkillsDir)
+	// A hand-made directory in the target isn't chaparral's to remove
+	if err := os.MkdirAll(filepath.Join(targetSkills, "by-hand"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	org.Manifest.Publish.Skills = []string{"test-skill"}
+	changes, err := DiffManifests(org, skills, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed := 0
+	for _, c := range changes {
+		if c.Kind == "removed" {
+			if !strings.HasPrefix(c.Path, filepath.Join(org.Manifest.SkillsDir, "second-skill")) {
+				t.Errorf("unexpected removal of %s", c.Path)
+			}
+			removed++
+		}
+	}
+	if removed == 0 {
+		t.Errorf("expected second-skill's files to be removed, got %+v", changes)
+	}
+
+	if _, err := WriteManifests(org, skills, Options{}); err != nil {
+		t.Fatalf("second write failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetSkills, "second-skill")); !os.IsNotExist(err) {
+		t.Error("expected second-skill to be gone from the target")
+	}
+	if _, err := os.Stat(filepath.Join(targetSkills, "test-skill", "SKILL.md")); err != nil {
+		t.Errorf("expected test-skill to stay: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetSkills, "by-hand")); err != nil {
+		t.Errorf("expected the hand-made directory to stay: %v", err)
+	}
+}
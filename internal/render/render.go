@@ -0,0 +1,400 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+const templateExt = ".tmpl"
+
+// generatedMarker is the text every rendered file's header starts with.
+// Files without it are never overwritten or removed.
+const generatedMarker = "generated by chaparral"
+
+// Template is a single *.tmpl file in an org's templates directory.
+type Template struct {
+	Name   string // path relative to the templates dir, e.g. "CLAUDE.md.tmpl"
+	Source string // absolute path to the template
+	Dest   string // output path relative to the repo root, e.g. "CLAUDE.md"
+}
+
+// Context is the data a template is executed with.
+type Context struct {
+	Org      string
+	Repo     string
+	Language string // primary language, detected or set via the "language" var
+	Vars     map[string]string
+}
+
+// Result describes what SyncRepo did for a single rendered file.
+type Result struct {
+	Repo   string
+	Path   string // relative to the repo root
+	Action string // "created", "updated", "exists", "skipped", "error", "removed"
+	Detail string
+}
+
+// FileStatus describes the state of a rendered file without changing anything.
+type FileStatus struct {
+	Repo   string
+	Path   string // relative to the repo root
+	State  string // "rendered", "outdated", "missing", "conflict", "error"
+	Detail string
+}
+
+// FindTemplates returns every *.tmpl file under dir. A missing dir has no templates.
+func FindTemplates(dir string) ([]Template, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	var templates []Template
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), templateExt) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		templates = append(templates, Template{
+			Name:   filepath.ToSlash(rel),
+			Source: path,
+			Dest:   strings.TrimSuffix(rel, templateExt),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding templates in %s: %w", dir, err)
+	}
+	return templates, nil
+}
+
+// NewContext builds the template context for one sibling repo.
+func NewContext(org config.Org, repo string) Context {
+	vars := org.RepoVars(repo)
+	language := vars["language"]
+	if language == "" {
+		language = DetectLanguage(filepath.Join(org.Path, repo))
+	}
+	return Context{
+		Org:      org.Manifest.Org,
+		Repo:     repo,
+		Language: language,
+		Vars:     vars,
+	}
+}
+
+// DetectLanguage guesses a repo's primary language from its build files.
+// Returns "" when nothing recognizable is found.
+func DetectLanguage(repoPath string) string {
+	markers := []struct {
+		file     string
+		language string
+	}{
+		{"go.mod", "Go"},
+		{"Cargo.toml", "Rust"},
+		{"tsconfig.json", "TypeScript"},
+		{"package.json", "JavaScript"},
+		{"pyproject.toml", "Python"},
+		{"requirements.txt", "Python"},
+		{"setup.py", "Python"},
+		{"Gemfile", "Ruby"},
+		{"mix.exs", "Elixir"},
+		{"Package.swift", "Swift"},
+		{"pom.xml", "Java"},
+		{"build.gradle", "Java"},
+		{"build.gradle.kts", "Kotlin"},
+	}
+	for _, m := range markers {
+		if _, err := os.Stat(filepath.Join(repoPath, m.file)); err == nil {
+			return m.language
+		}
+	}
+	return ""
+}
+
+// OrgTemplates returns every template rendered into an org's sibling repos:
+// the templates directory's, and the org CLAUDE.md when claude_md names a
+// template, which is rendered to each repo's CLAUDE.md instead of being
+// linked into the org directory.
+func OrgTemplates(org config.Org) ([]Template, error) {
+	templates, err := FindTemplates(org.TemplatesPath())
+	if err != nil {
+		return nil, err
+	}
+	if IsTemplate(org.Manifest.ClaudeMD) {
+		templates = append(templates, Template{
+			Name:   filepath.ToSlash(org.Manifest.ClaudeMD),
+			Source: org.ClaudeMDPath(),
+			Dest:   "CLAUDE.md",
+		})
+	}
+	return templates, nil
+}
+
+// IsTemplate reports whether a file name marks it as a template.
+func IsTemplate(name string) bool {
+	return strings.HasSuffix(name, templateExt)
+}
+
+// Render executes a template and returns the file content, including the
+// generated-file header when the output type has a comment syntax to put it
+// in. Unknown variables are an error rather than "<no value>".
+func Render(t Template, ctx Context) (string, error) {
+	data, err := os.ReadFile(t.Source)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", t.Name, err)
+	}
+
+	tmpl, err := template.New(t.Name).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return "", fmt.Errorf("parsing %s: %w", t.Name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ctx); err != nil {
+		return "", fmt.Errorf("rendering %s: %w", t.Name, err)
+	}
+
+	header, ok := headerFor(t)
+	if !ok {
+		return buf.String(), nil
+	}
+	return insertHeader(buf.String(), header), nil
+}
+
+// SyncRepo renders every template into a repo. Files that exist but weren't
+// generated by chaparral are skipped, never overwritten.
+func SyncRepo(org config.Org, repo string, templates []Template) []Result {
+	repoPath := filepath.Join(org.Path, repo)
+	ctx := NewContext(org, repo)
+	state := loadState(repoPath)
+	stateChanged := false
+
+	var results []Result
+	for _, t := range templates {
+		result := Result{Repo: repo, Path: filepath.ToSlash(t.Dest)}
+		dest := filepath.Join(repoPath, t.Dest)
+
+		content, err := Render(t, ctx)
+		if err != nil {
+			result.Action = "error"
+			result.Detail = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		existing, err := os.ReadFile(dest)
+		switch {
+		case os.IsNotExist(err):
+			result.Action = "created"
+		case err != nil:
+			result.Action = "error"
+			result.Detail = err.Error()
+		case string(existing) == content:
+			result.Action = "exists"
+		case !state.owns(result.Path, existing):
+			result.Action = "skipped"
+			result.Detail = conflictDetail(t)
+		default:
+			result.Action = "updated"
+		}
+		switch result.Action {
+		case "created", "updated":
+			if err := writeInRepo(repoPath, dest, content); err != nil {
+				result.Action = "error"
+				result.Detail = err.Error()
+			}
+		}
+
+		// Unmarked output is remembered by hash so it can be updated later
+		if _, marked := headerFor(t); !marked && result.Action != "error" && result.Action != "skipped" {
+			if hash := contentHash([]byte(content)); state[result.Path] != hash {
+				state[result.Path] = hash
+				stateChanged = true
+			}
+		}
+		results = append(results, result)
+	}
+
+	if stateChanged {
+		if err := state.save(repoPath); err != nil {
+			results = append(results, Result{Repo: repo, Path: stateFile, Action: "error", Detail: err.Error()})
+		}
+	}
+	return results
+}
+
+// StatusRepo reports whether each rendered file in a repo is up to date.
+func StatusRepo(org config.Org, repo string, templates []Template) []FileStatus {
+	repoPath := filepath.Join(org.Path, repo)
+	ctx := NewContext(org, repo)
+	state := loadState(repoPath)
+
+	var statuses []FileStatus
+	for _, t := range templates {
+		st := FileStatus{Repo: repo, Path: filepath.ToSlash(t.Dest)}
+
+		content, err := Render(t, ctx)
+		if err != nil {
+			st.State = "error"
+			st.Detail = err.Error()
+			statuses = append(statuses, st)
+			continue
+		}
+
+		existing, err := os.ReadFile(filepath.Join(repoPath, t.Dest))
+		switch {
+		case os.IsNotExist(err):
+			st.State = "missing"
+		case err != nil:
+			st.State = "error"
+			st.Detail = err.Error()
+		case string(existing) == content:
+			st.State = "rendered"
+		case !state.owns(st.Path, existing):
+			st.State = "conflict"
+			st.Detail = conflictDetail(t)
+		default:
+			st.State = "outdated"
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses
+}
+
+// StatusOrg reports rendered file state for every sibling repo in an org.
+func StatusOrg(org config.Org) ([]FileStatus, error) {
+	templates, err := OrgTemplates(org)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []FileStatus
+	for _, repo := range org.Repos {
+		statuses = append(statuses, StatusRepo(org, repo, templates)...)
+	}
+	return statuses, nil
+}
+
+// RemoveRepo deletes rendered files from a repo. Only files carrying the
+// generated header, or unchanged since chaparral rendered them, are removed.
+func RemoveRepo(org config.Org, repo string, templates []Template) []Result {
+	repoPath := filepath.Join(org.Path, repo)
+	state := loadState(repoPath)
+	stateChanged := false
+
+	var results []Result
+	for _, t := range templates {
+		path := filepath.ToSlash(t.Dest)
+		dest := filepath.Join(repoPath, t.Dest)
+		existing, err := os.ReadFile(dest)
+		if err != nil || !state.owns(path, existing) {
+			continue
+		}
+		if err := os.Remove(dest); err != nil {
+			continue
+		}
+		if _, ok := state[path]; ok {
+			delete(state, path)
+			stateChanged = true
+		}
+		results = append(results, Result{Repo: repo, Path: path, Action: "removed"})
+	}
+
+	if stateChanged {
+		if err := state.save(repoPath); err != nil {
+			results = append(results, Result{Repo: repo, Path: stateFile, Action: "error", Detail: err.Error()})
+		}
+	}
+	return results
+}
+
+// writeInRepo writes a rendered file, refusing to follow symlinked directories
+// out of the repo (e.g. into a linked skill in the brand repo).
+func writeInRepo(repoPath, dest, content string) error {
+	dir := filepath.Dir(dest)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+
+	realRepo, err := filepath.EvalSymlinks(repoPath)
+	if err != nil {
+		return err
+	}
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(realRepo, realDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s resolves outside the repo", dir)
+	}
+
+	return os.WriteFile(dest, []byte(content), 0644)
+}
+
+// headerFor returns the generated-file comment for a template's output type.
+// ok is false for types without a comment syntax, like JSON: their output
+// goes unmarked, and the repo's render state records what was written.
+func headerFor(t Template) (string, bool) {
+	text := fmt.Sprintf("%s from %s — edit the template in the brand repo, not this file", generatedMarker, t.Name)
+	switch strings.ToLower(filepath.Ext(t.Dest)) {
+	case ".md", ".markdown":
+		return "<!-- " + text + " -->\n", true
+	case ".yaml", ".yml", ".toml", ".sh", ".py", ".rb", ".conf", ".ini":
+		return "# " + text + "\n", true
+	default:
+		return "", false
+	}
+}
+
+// conflictDetail explains why an existing file won't be overwritten.
+func conflictDetail(t Template) string {
+	if _, ok := headerFor(t); !ok {
+		return "file exists and differs from what chaparral last rendered"
+	}
+	return "file exists and wasn't generated by chaparral"
+}
+
+// insertHeader puts the header at the top of the file, or just after a
+// leading frontmatter block so frontmatter stays on the first line.
+func insertHeader(content, header string) string {
+	if strings.HasPrefix(content, "---\n") {
+		if end := strings.Index(content[4:], "\n---\n"); end >= 0 {
+			split := 4 + end + len("\n---\n")
+			return content[:split] + header + content[split:]
+		}
+	}
+	return header + content
+}
+
+// IsGenerated checks the first lines of a file for the header chaparral
+// writes into rendered files.
+func IsGenerated(data []byte) bool {
+	head := string(data)
+	if len(head) > 4096 {
+		head = head[:4096]
+	}
+	for _, line := range strings.SplitN(head, "\n", 32) {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "<!-- "+generatedMarker) || strings.HasPrefix(line, "# "+generatedMarker) {
+			return true
+		}
+	}
+	return false
+}
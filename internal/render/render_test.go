@@ -0,0 +1,246 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// setupOrg creates an org with a brand repo holding one template and a
+// single sibling repo containing a go.mod.
+func setupOrg(t *testing.T, tmpl string) (config.Org, []Template) {
+	t.Helper()
+	orgDir := t.TempDir()
+
+	templatesDir := filepath.Join(orgDir, "brand", "org", "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templatesDir, "CLAUDE.md.tmpl"), []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repoDir := filepath.Join(orgDir, "toyon")
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "go.mod"), []byte("module toyon\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	org := config.Org{
+		Name:      "test-org",
+		Path:      orgDir,
+		BrandRepo: "brand",
+		Manifest: config.Manifest{
+			Org:          "test-org",
+			TemplatesDir: "org/templates",
+			Vars:         map[string]string{"team": "platform"},
+			Repos: map[string]config.RepoConfig{
+				"toyon": {Vars: map[string]string{"team": "web"}},
+			},
+		},
+		Repos: []string{"toyon"},
+	}
+
+	templates, err := FindTemplates(org.TemplatesPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return org, templates
+}
+
+func TestFindTemplates(t *testing.T) {
+	_, templates := setupOrg(t, "hello\n")
+
+	if len(templates) != 1 {
+		t.Fatalf("expected 1 template, got %d", len(templates))
+	}
+	if templates[0].Dest != "CLAUDE.md" {
+		t.Errorf("dest = %q, want CLAUDE.md", templates[0].Dest)
+	}
+}
+
+func TestSyncRepo_RendersContext(t *testing.T) {
+	org, templates := setupOrg(t, "# {{.Repo}} ({{.Language}}) for {{.Org}}, team {{.Vars.team}}\n")
+
+	results := SyncRepo(org, "toyon", templates)
+	if len(results) != 1 || results[0].Action != "created" {
+		t.Fatalf("expected one created result, got %+v", results)
+	}
+
+	data, err := os.ReadFile(filepath.Join(org.Path, "toyon", "CLAUDE.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# toyon (Go) for test-org, team web") {
+		t.Errorf("unexpected rendered content:\n%s", data)
+	}
+	if !strings.HasPrefix(string(data), "<!-- generated by chaparral") {
+		t.Errorf("expected generated header, got:\n%s", data)
+	}
+
+	// Second sync is a no-op
+	results = SyncRepo(org, "toyon", templates)
+	if results[0].Action != "exists" {
+		t.Errorf("expected exists on second sync, got %q", results[0].Action)
+	}
+}
+
+func TestSyncRepo_SkipsUnmanagedFile(t *testing.T) {
+	org, templates := setupOrg(t, "shared\n")
+	dest := filepath.Join(org.Path, "toyon", "CLAUDE.md")
+	if err := os.WriteFile(dest, []byte("# hand written\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := SyncRepo(org, "toyon", templates)
+	if results[0].Action != "skipped" {
+		t.Errorf("expected skipped, got %q", results[0].Action)
+	}
+
+	data, _ := os.ReadFile(dest)
+	if string(data) != "# hand written\n" {
+		t.Error("unmanaged file was overwritten")
+	}
+}
+
+func TestSyncRepo_UnknownVar(t *testing.T) {
+	org, templates := setupOrg(t, "{{.Vars.nope}}\n")
+
+	results := SyncRepo(org, "toyon", templates)
+	if results[0].Action != "error" {
+		t.Errorf("expected error for unknown var, got %q", results[0].Action)
+	}
+}
+
+func TestStatusRepo_Outdated(t *testing.T) {
+	org, templates := setupOrg(t, "version one\n")
+
+	statuses := StatusRepo(org, "toyon", templates)
+	if statuses[0].State != "missing" {
+		t.Errorf("expected missing before sync, got %q", statuses[0].State)
+	}
+
+	SyncRepo(org, "toyon", templates)
+	statuses = StatusRepo(org, "toyon", templates)
+	if statuses[0].State != "rendered" {
+		t.Errorf("expected rendered after sync, got %q", statuses[0].State)
+	}
+
+	if err := os.WriteFile(templates[0].Source, []byte("version two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	statuses = StatusRepo(org, "toyon", templates)
+	if statuses[0].State != "outdated" {
+		t.Errorf("expected outdated after template change, got %q", statuses[0].State)
+	}
+}
+
+func TestInsertHeader_AfterFrontmatter(t *testing.T) {
+	got := insertHeader("---\nname: x\n---\nbody\n", "<!-- h -->\n")
+	want := "---\nname: x\n---\n<!-- h -->\nbody\n"
+	if got != want {
+		t.Errorf("insertHeader = %q, want %q", got, want)
+	}
+}
+
+func TestSyncRepo_UnmarkableOutput(t *testing.T) {
+	org, _ := setupOrg(t, "hello\n")
+	source := filepath.Join(org.TemplatesPath(), "settings.json.tmpl")
+	if err := os.WriteFile(source, []byte(`{"repo": "{{.Repo}}"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	templates := []Template{{Name: "settings.json.tmpl", Source: source, Dest: "settings.json"}}
+
+	results := SyncRepo(org, "toyon", templates)
+	if len(results) != 1 || results[0].Action != "created" {
+		t.Fatalf("expected JSON to render without a header, got %+v", results)
+	}
+	data, err := os.ReadFile(filepath.Join(org.Path, "toyon", "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"repo": "toyon"}`+"\n" {
+		t.Errorf("unexpected rendered content:\n%s", data)
+	}
+	if results := SyncRepo(org, "toyon", templates); results[0].Action != "exists" {
+		t.Errorf("expected unchanged output to exist, got %+v", results[0])
+	}
+
+	// The render state lets a template change update it
+	if err := os.WriteFile(source, []byte(`{"name": "{{.Repo}}"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if statuses := StatusRepo(org, "toyon", templates); statuses[0].State != "outdated" {
+		t.Errorf("expected outdated after a template change, got %+v", statuses[0])
+	}
+	if results := SyncRepo(org, "toyon", templates); results[0].Action != "updated" {
+		t.Fatalf("expected the JSON output to be updated, got %+v", results)
+	}
+
+	// A hand edit makes it the repo's own file
+	dest := filepath.Join(org.Path, "toyon", "settings.json")
+	if err := os.WriteFile(dest, []byte(`{"name": "mine"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if statuses := StatusRepo(org, "toyon", templates); statuses[0].State != "conflict" {
+		t.Errorf("expected a conflict after a hand edit, got %+v", statuses[0])
+	}
+	if results := RemoveRepo(org, "toyon", templates); len(results) != 0 {
+		t.Errorf("expected a hand-edited file to be kept, got %+v", results)
+	}
+}
+
+func TestRemoveRepo_UnmarkableOutput(t *testing.T) {
+	org, _ := setupOrg(t, "hello\n")
+	source := filepath.Join(org.TemplatesPath(), "settings.json.tmpl")
+	if err := os.WriteFile(source, []byte(`{"repo": "{{.Repo}}"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	templates := []Template{{Name: "settings.json.tmpl", Source: source, Dest: "settings.json"}}
+	SyncRepo(org, "toyon", templates)
+
+	results := RemoveRepo(org, "toyon", templates)
+	if len(results) != 1 || results[0].Action != "removed" {
+		t.Fatalf("expected the rendered JSON to be removed, got %+v", results)
+	}
+	repoPath := filepath.Join(org.Path, "toyon")
+	if _, err := os.Stat(filepath.Join(repoPath, "settings.json")); !os.IsNotExist(err) {
+		t.Error("expected settings.json to be gone")
+	}
+	if _, err := os.Stat(filepath.Join(repoPath, stateFile)); !os.IsNotExist(err) {
+		t.Error("expected the empty render state to be removed")
+	}
+}
+
+func TestOrgTemplates_ClaudeMD(t *testing.T) {
+	org, _ := setupOrg(t, "hello\n")
+	org.Manifest.ClaudeMD = "org/CLAUDE.md.tmpl"
+	if err := os.WriteFile(org.ClaudeMDPath(), []byte("# {{.Org}} in {{.Repo}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := OrgTemplates(org)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 2 {
+		t.Fatalf("expected the templates dir's template and the org CLAUDE.md, got %+v", templates)
+	}
+	content, err := Render(templates[1], NewContext(org, "toyon"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(content, "# test-org in toyon") || !strings.Contains(content, "from org/CLAUDE.md.tmpl") {
+		t.Errorf("unexpected rendered CLAUDE.md:\n%s", content)
+	}
+
+	org.Manifest.ClaudeMD = "org/CLAUDE.md"
+	if templates, _ := OrgTemplates(org); len(templates) != 1 {
+		t.Errorf("expected a plain claude_md not to be rendered, got %+v", templates)
+	}
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// stateFile records, per repo, what chaparral last rendered into files that
+// can't carry the generated header, so it can still tell its own output
+// apart from a hand-written file.
+const stateFile = ".claude/.chaparral-rendered.json"
+
+// renderState maps a rendered file's repo-relative path to the hash of the
+// content chaparral last wrote there.
+type renderState map[string]string
+
+// loadState reads a repo's render state. A missing or unreadable file is
+// empty: chaparral then claims none of the unmarked files.
+func loadState(repoPath string) renderState {
+	state := make(renderState)
+	data, err := os.ReadFile(filepath.Join(repoPath, stateFile))
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return make(renderState)
+	}
+	return state
+}
+
+// save writes the state back, removing the file once nothing is tracked.
+func (s renderState) save(repoPath string) error {
+	path := filepath.Join(repoPath, stateFile)
+	if len(s) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeInRepo(repoPath, path, string(data)+"\n"); err != nil {
+		return fmt.Errorf("writing %s: %w", stateFile, err)
+	}
+	return nil
+}
+
+// owns reports whether chaparral may overwrite or remove a file: it carries
+// the generated header, or it's unchanged since chaparral last rendered it.
+func (s renderState) owns(path string, data []byte) bool {
+	return IsGenerated(data) || s[path] == contentHash(data)
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,57 @@
+package similarity
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// stopWords are too common to say anything about what a text is about.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "into": true,
+	"is": true, "it": true, "its": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "with": true, "you": true,
+	"your": true,
+}
+
+// Words splits text into lowercase words, dropping punctuation and stop words.
+func Words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := fields[:0]
+	for _, w := range fields {
+		if !stopWords[w] {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// Cosine returns the cosine similarity of two texts' word counts, from 0
+// (nothing in common) to 1 (the same words in the same proportions).
+func Cosine(a, b string) float64 {
+	ca, cb := counts(Words(a)), counts(Words(b))
+	if len(ca) == 0 || len(cb) == 0 {
+		return 0
+	}
+
+	var dot, na, nb float64
+	for w, n := range ca {
+		dot += float64(n * cb[w])
+		na += float64(n * n)
+	}
+	for _, n := range cb {
+		nb += float64(n * n)
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+func counts(words []string) map[string]int {
+	c := make(map[string]int, len(words))
+	for _, w := range words {
+		c[w]++
+	}
+	return c
+}
@@ -0,0 +1,37 @@
+package similarity
+
+import "testing"
+
+func TestWords(t *testing.T) {
+	got := Words("Write in the Manzanita voice: warm, direct.")
+	want := []string{"write", "manzanita", "voice", "warm", "direct"}
+	if len(got) != len(want) {
+		t.Fatalf("Words = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Words[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCosine(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		min, max float64
+	}{
+		{"identical", "review go code for bugs", "Review Go code for bugs.", 0.999, 1.001},
+		{"disjoint", "review go code", "write release notes", 0, 0.001},
+		{"close", "review go code for bugs and style", "review go code for bugs", 0.8, 0.95},
+		{"empty", "", "anything", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Cosine(tt.a, tt.b)
+			if got < tt.min || got > tt.max {
+				t.Errorf("Cosine = %.3f, want between %.3f and %.3f", got, tt.min, tt.max)
+			}
+		})
+	}
+}
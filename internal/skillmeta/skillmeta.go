@@ -1,61 +1,225 @@
 package skillmeta
 
 import (
-	"bufio"
+	"bytes"
 	"fmt"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Frontmatter holds the parsed metadata from a SKILL.md file.
 type Frontmatter struct {
-	Name        string
-	Description string
-	License     string
+	Name         string
+	Description  string
+	License      string
+	Version      string         // pinned publish version, if set
+	Requires     []string       // other skills this one depends on
+	AllowedTools []string       // "allowed-tools", from a list or comma-separated string
+	Metadata     map[string]any // nested "metadata" block
+	Fields       map[string]any // every top-level field, including ones not listed above
+	BodyOffset   int            // byte offset where the markdown body starts
+}
+
+// ParseError is a frontmatter syntax error. Line counts from the top of
+// SKILL.md, so it points at the same line an editor would.
+type ParseError struct {
+	Path string
+	Line int
+	Msg  string
 }
 
-// ParseFrontmatter reads SKILL.md frontmatter (key: value pairs between --- delimiters).
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+	}
+	return fmt.Sprintf("%s: line %d: %s", e.Path, e.Line, e.Msg)
+}
+
+var yamlLineRe = regexp.MustCompile(`line (\d+): (.*)`)
+
+// plainValueRe matches a top-level "key: value" line with an unquoted value.
+var plainValueRe = regexp.MustCompile(`^([A-Za-z0-9_-]+):[ \t]+([^"'\[{|>&*!#\s].*)$`)
+
+// ParseFrontmatter reads SKILL.md frontmatter (YAML between --- delimiters).
 // The file must start with --- on the first line. Parsing ends at the closing --- or EOF.
 func ParseFrontmatter(path string) (Frontmatter, error) {
-	f, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return Frontmatter{}, fmt.Errorf("can't open %s: %w", path, err)
 	}
-	defer f.Close()
-
-	scanner := bufio.NewScanner(f)
+	return Parse(path, data)
+}
 
-	// First line must be ---
-	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "---" {
+// Parse parses SKILL.md content. path is only used in error messages.
+func Parse(path string, data []byte) (Frontmatter, error) {
+	yamlText, bodyOffset, ok := Split(data)
+	if !ok {
 		return Frontmatter{}, fmt.Errorf("SKILL.md at %s has no frontmatter (missing opening ---)", path)
 	}
 
-	var fm Frontmatter
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.TrimSpace(line) == "---" {
-			break
+	fields, err := decode(path, yamlText)
+	if err != nil {
+		return Frontmatter{}, err
+	}
+
+	fm := Frontmatter{
+		Name:         stringField(fields["name"]),
+		Description:  stringField(fields["description"]),
+		License:      stringField(fields["license"]),
+		Version:      stringField(fields["version"]),
+		Requires:     listField(fields["requires"]),
+		AllowedTools: listField(fields["allowed-tools"]),
+		Fields:       fields,
+		BodyOffset:   bodyOffset,
+	}
+	if meta, ok := fields["metadata"].(map[string]any); ok {
+		fm.Metadata = meta
+	}
+	return fm, nil
+}
+
+// Split separates the frontmatter YAML from the body. It returns the YAML
+// text, the byte offset of the body, and false if there's no opening ---.
+// Without a closing ---, everything after the opening line is frontmatter.
+func Split(data []byte) (string, int, bool) {
+	firstEnd := bytes.IndexByte(data, '\n')
+	first := data
+	if firstEnd >= 0 {
+		first = data[:firstEnd]
+	}
+	if strings.TrimSpace(string(first)) != "---" {
+		return "", 0, false
+	}
+	if firstEnd < 0 {
+		return "", len(data), true
+	}
+
+	start := firstEnd + 1
+	pos := start
+	for pos < len(data) {
+		end := bytes.IndexByte(data[pos:], '\n')
+		lineEnd := len(data)
+		next := len(data)
+		if end >= 0 {
+			lineEnd = pos + end
+			next = lineEnd + 1
 		}
-		parts := strings.SplitN(line, ":", 2)
-		if len(parts) != 2 {
-			continue
+		if strings.TrimSpace(string(data[pos:lineEnd])) == "---" {
+			return string(data[start:pos]), next, true
 		}
-		key := strings.TrimSpace(parts[0])
-		val := strings.TrimSpace(parts[1])
-
-		switch key {
-		case "name":
-			fm.Name = val
-		case "description":
-			fm.Description = val
-		case "license":
-			fm.License = val
+		pos = next
+	}
+	return string(data[start:]), len(data), true
+}
+
+// decode unmarshals frontmatter YAML into a map. Values like
+// "description: Review code: be thorough" aren't valid YAML but are common in
+// hand-written SKILL.md files, so a failed parse is retried with such values
+// quoted before the error is reported.
+func decode(path, yamlText string) (map[string]any, error) {
+	var fields map[string]any
+	err := yaml.Unmarshal([]byte(yamlText), &fields)
+	if err == nil {
+		return fields, nil
+	}
+
+	if repaired := quotePlainValues(yamlText); repaired != yamlText {
+		var retry map[string]any
+		if yaml.Unmarshal([]byte(repaired), &retry) == nil {
+			return retry, nil
+		}
+	}
+
+	return nil, toParseError(path, err)
+}
+
+// quotePlainValues double-quotes top-level plain values that contain ": ".
+func quotePlainValues(yamlText string) string {
+	lines := strings.Split(yamlText, "\n")
+	for i, line := range lines {
+		m := plainValueRe.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil || !strings.Contains(m[2], ": ") {
+			continue
 		}
+		lines[i] = m[1] + ": " + strconv.Quote(m[2])
 	}
+	return strings.Join(lines, "\n")
+}
 
-	if err := scanner.Err(); err != nil {
-		return Frontmatter{}, fmt.Errorf("reading %s: %w", path, err)
+// toParseError converts a yaml.v3 error into a ParseError with the line
+// number shifted past the opening --- delimiter.
+func toParseError(path string, err error) *ParseError {
+	msg := err.Error()
+	if te, ok := err.(*yaml.TypeError); ok && len(te.Errors) > 0 {
+		msg = te.Errors[0]
 	}
+	msg = strings.TrimPrefix(msg, "yaml: ")
 
-	return fm, nil
+	if m := yamlLineRe.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return &ParseError{Path: path, Line: line + 1, Msg: m[2]}
+	}
+	return &ParseError{Path: path, Msg: msg}
+}
+
+// stringField renders a scalar YAML value as a string. Missing and null
+// values are empty.
+func stringField(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// listField accepts either a YAML list or a comma-separated string.
+// Commas inside parentheses (as in "Bash(git add:*, git commit:*)") don't split.
+func listField(v any) []string {
+	switch val := v.(type) {
+	case []any:
+		var items []string
+		for _, item := range val {
+			if s := stringField(item); s != "" {
+				items = append(items, s)
+			}
+		}
+		return items
+	case string:
+		return splitList(val)
+	default:
+		return nil
+	}
+}
+
+func splitList(s string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				if item := strings.TrimSpace(s[start:i]); item != "" {
+					items = append(items, item)
+				}
+				start = i + 1
+			}
+		}
+	}
+	if item := strings.TrimSpace(s[start:]); item != "" {
+		items = append(items, item)
+	}
+	return items
 }
@@ -1,6 +1,7 @@
 package skillmeta
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -125,3 +126,160 @@ description: Review code for bugs, security, and performance: be thorough
 		t.Errorf("description = %q, want %q", fm.Description, "Review code for bugs, security, and performance: be thorough")
 	}
 }
+
+func TestParseFrontmatter_RequiresInline(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSkillMD(t, dir, `---
+name: release-notes
+description: Write release notes
+requires: [brand-voice, "changelog-style"]
+---
+`)
+
+	fm, err := ParseFrontmatter(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"brand-voice", "changelog-style"}
+	if len(fm.Requires) != len(want) || fm.Requires[0] != want[0] || fm.Requires[1] != want[1] {
+		t.Errorf("requires = %v, want %v", fm.Requires, want)
+	}
+}
+
+func TestParseFrontmatter_RequiresBlockList(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSkillMD(t, dir, `---
+name: release-notes
+requires:
+  - brand-voice
+  - changelog-style
+description: Write release notes
+---
+`)
+
+	fm, err := ParseFrontmatter(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fm.Requires) != 2 || fm.Requires[1] != "changelog-style" {
+		t.Errorf("requires = %v, want [brand-voice changelog-style]", fm.Requires)
+	}
+	if fm.Description != "Write release notes" {
+		t.Errorf("description = %q, want %q", fm.Description, "Write release notes")
+	}
+}
+
+func TestParseFrontmatter_FoldedDescription(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSkillMD(t, dir, `---
+name: brand-voice
+description: >
+  Write in the Manzanita Research voice.
+  Use when drafting copy.
+---
+`)
+
+	fm, err := ParseFrontmatter(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Write in the Manzanita Research voice. Use when drafting copy."
+	if fm.Description != want {
+		t.Errorf("description = %q, want %q", fm.Description, want)
+	}
+}
+
+func TestParseFrontmatter_QuotedColons(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSkillMD(t, dir, `---
+name: review-code
+description: "Review code: bugs, security, # and performance"
+---
+`)
+
+	fm, err := ParseFrontmatter(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fm.Description != "Review code: bugs, security, # and performance" {
+		t.Errorf("description = %q", fm.Description)
+	}
+}
+
+func TestParseFrontmatter_AllFields(t *testing.T) {
+	dir := t.TempDir()
+	content := `---
+name: release-notes
+description: Write release notes
+allowed-tools:
+  - Read
+  - Bash(git log:*)
+metadata:
+  owner: docs-team
+  tier: 2
+x-internal: true
+---
+# Release notes
+`
+	path := writeSkillMD(t, dir, content)
+
+	fm, err := ParseFrontmatter(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fm.AllowedTools) != 2 || fm.AllowedTools[1] != "Bash(git log:*)" {
+		t.Errorf("allowed-tools = %v", fm.AllowedTools)
+	}
+	if fm.Metadata["owner"] != "docs-team" || fm.Metadata["tier"] != 2 {
+		t.Errorf("metadata = %v", fm.Metadata)
+	}
+	if fm.Fields["x-internal"] != true {
+		t.Errorf("unknown field x-internal missing from Fields: %v", fm.Fields)
+	}
+	if body := content[fm.BodyOffset:]; body != "# Release notes\n" {
+		t.Errorf("body = %q, want %q", body, "# Release notes\n")
+	}
+}
+
+func TestParseFrontmatter_AllowedToolsString(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSkillMD(t, dir, `---
+name: committer
+description: Commit changes
+allowed-tools: Read, Bash(git add:*, git commit:*), Grep
+---
+`)
+
+	fm, err := ParseFrontmatter(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"Read", "Bash(git add:*, git commit:*)", "Grep"}
+	if len(fm.AllowedTools) != len(want) {
+		t.Fatalf("allowed-tools = %v, want %v", fm.AllowedTools, want)
+	}
+	for i := range want {
+		if fm.AllowedTools[i] != want[i] {
+			t.Errorf("allowed-tools[%d] = %q, want %q", i, fm.AllowedTools[i], want[i])
+		}
+	}
+}
+
+func TestParseFrontmatter_SyntaxErrorLine(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSkillMD(t, dir, `---
+name: brand-voice
+description: fine
+  bad: indentation
+---
+`)
+
+	_, err := ParseFrontmatter(path)
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *ParseError, got %v", err)
+	}
+	if perr.Line != 4 {
+		t.Errorf("line = %d, want 4 (%s)", perr.Line, perr.Msg)
+	}
+}
@@ -0,0 +1,174 @@
+package skillmeta
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/manzanita-research/chaparral/internal/textdiff"
+)
+
+// Field is a top-level frontmatter key and the value to give it.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// SetFile updates frontmatter fields in a SKILL.md file in place.
+func SetFile(path string, fields ...Field) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("can't open %s: %w", path, err)
+	}
+
+	updated, err := Set(path, data, fields...)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(updated, data) {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, updated, info.Mode().Perm())
+}
+
+// Set updates top-level frontmatter fields in SKILL.md content. Only the lines
+// holding a changed field are rewritten; comments, key order and the markdown
+// body are left byte-for-byte as they were. Fields that don't exist yet are
+// appended before the closing ---. path is only used in error messages.
+func Set(path string, data []byte, fields ...Field) ([]byte, error) {
+	if !bytes.Contains(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	yamlText, _, ok := Split(data)
+	if !ok {
+		return nil, fmt.Errorf("SKILL.md at %s has no frontmatter (missing opening ---)", path)
+	}
+	yamlStart := bytes.IndexByte(data, '\n') + 1
+	yamlEnd := yamlStart + len(yamlText)
+
+	lines := textdiff.SplitLines(yamlText)
+	for _, f := range fields {
+		root, err := parseNode(path, strings.Join(lines, ""))
+		if err != nil {
+			return nil, err
+		}
+
+		encoded, err := encodeField(f)
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s: %w", f.Key, err)
+		}
+
+		first, last, comment := fieldLines(root, lines, f.Key)
+		if first < 0 {
+			if n := len(lines); n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+				lines[n-1] += "\n"
+			}
+			lines = append(lines, encoded...)
+			continue
+		}
+
+		// Keep a trailing comment on single-line values
+		if comment != "" && len(encoded) == 1 {
+			encoded[0] = strings.TrimSuffix(encoded[0], "\n") + " " + comment + "\n"
+		}
+		if !strings.HasSuffix(lines[last], "\n") {
+			n := len(encoded) - 1
+			encoded[n] = strings.TrimSuffix(encoded[n], "\n")
+		}
+		lines = append(lines[:first], append(encoded, lines[last+1:]...)...)
+	}
+
+	var out bytes.Buffer
+	out.Write(data[:yamlStart])
+	out.WriteString(strings.Join(lines, ""))
+	out.Write(data[yamlEnd:])
+
+	// Never hand back frontmatter that no longer parses
+	if _, err := Parse(path, out.Bytes()); err != nil {
+		return nil, fmt.Errorf("updated frontmatter doesn't parse: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// parseNode parses frontmatter into a yaml.v3 node tree for its line positions.
+// Like decode, it falls back to quoting plain values containing ": "; that
+// rewrite never moves a line, so positions still match the original text.
+func parseNode(path, yamlText string) (*yaml.Node, error) {
+	var doc yaml.Node
+	err := yaml.Unmarshal([]byte(yamlText), &doc)
+	if err != nil {
+		if retryErr := yaml.Unmarshal([]byte(quotePlainValues(yamlText)), &doc); retryErr != nil {
+			return nil, toParseError(path, err)
+		}
+	}
+
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode}, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, &ParseError{Path: path, Line: root.Line + 1, Msg: "frontmatter must be a mapping of fields"}
+	}
+	return root, nil
+}
+
+// fieldLines finds the zero-based line range a top-level key occupies, along
+// with any comment trailing its value. first is -1 if the key isn't present.
+// Blank lines and comments between this field and the next stay where they are.
+func fieldLines(root *yaml.Node, lines []string, key string) (first, last int, comment string) {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		k, v := root.Content[i], root.Content[i+1]
+		if k.Value != key {
+			continue
+		}
+
+		first = k.Line - 1
+		last = len(lines) - 1
+		if i+2 < len(root.Content) {
+			last = root.Content[i+2].Line - 2
+		}
+		for last > first {
+			trimmed := strings.TrimSpace(lines[last])
+			if trimmed != "" && !strings.HasPrefix(lines[last], "#") {
+				break
+			}
+			last--
+		}
+
+		comment = v.LineComment
+		if comment == "" {
+			comment = k.LineComment
+		}
+		return first, last, comment
+	}
+	return -1, -1, ""
+}
+
+// encodeField renders a single "key: value" entry as YAML lines, each ending
+// in a newline. Lists come out in block style with two-space indentation.
+func encodeField(f Field) ([]string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	var value yaml.Node
+	if err := value.Encode(f.Value); err != nil {
+		return nil, err
+	}
+	node.Content = []*yaml.Node{{Kind: yaml.ScalarNode, Value: f.Key}, &value}
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return textdiff.SplitLines(buf.String()), nil
+}
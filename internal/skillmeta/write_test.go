@@ -0,0 +1,127 @@
+package skillmeta
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSet_PreservesEverythingElse(t *testing.T) {
+	input := `---
+# Owned by the docs team
+name: release-notes
+version: 1.0.0 # bumped by publish
+description: >
+  Write release notes.
+  Use after tagging.
+
+license: MIT
+---
+# Release notes
+
+Body stays   exactly as it was.
+`
+	got, err := Set("SKILL.md", []byte(input), Field{Key: "version", Value: "1.1.0"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := strings.Replace(input, "version: 1.0.0 # bumped by publish", "version: 1.1.0 # bumped by publish", 1)
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSet_MultiLineValue(t *testing.T) {
+	input := `---
+name: release-notes
+description: >
+  Write release notes.
+  Use after tagging.
+# keep this comment
+license: MIT
+---
+body
+`
+	got, err := Set("SKILL.md", []byte(input), Field{Key: "description", Value: "Write release notes"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `---
+name: release-notes
+description: Write release notes
+# keep this comment
+license: MIT
+---
+body
+`
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSet_AddsMissingFields(t *testing.T) {
+	input := "---\nname: brand-voice\n---\n# Brand voice\n"
+
+	got, err := Set("SKILL.md", []byte(input),
+		Field{Key: "license", Value: "MIT"},
+		Field{Key: "tags", Value: []string{"writing", "brand"}},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "---\nname: brand-voice\nlicense: MIT\ntags:\n  - writing\n  - brand\n---\n# Brand voice\n"
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	fm, err := Parse("SKILL.md", got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fm.License != "MIT" {
+		t.Errorf("license = %q, want MIT", fm.License)
+	}
+}
+
+func TestSet_QuotesWhenNeeded(t *testing.T) {
+	input := "---\nname: review\n---\n"
+
+	got, err := Set("SKILL.md", []byte(input), Field{Key: "description", Value: "Review code: be thorough"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fm, err := Parse("SKILL.md", got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fm.Description != "Review code: be thorough" {
+		t.Errorf("description = %q", fm.Description)
+	}
+}
+
+func TestSet_NoFrontmatter(t *testing.T) {
+	if _, err := Set("SKILL.md", []byte("# Just markdown\n"), Field{Key: "name", Value: "x"}); err == nil {
+		t.Error("expected error for SKILL.md without frontmatter")
+	}
+}
+
+func TestSetFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeSkillMD(t, dir, "---\nname: brand-voice\nversion: 0.1.0\n---\nbody\n")
+
+	if err := SetFile(path, Field{Key: "version", Value: "0.2.0"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "---\nname: brand-voice\nversion: 0.2.0\n---\nbody\n" {
+		t.Errorf("got:\n%s", data)
+	}
+}
@@ -0,0 +1,65 @@
+package textdiff
+
+import "testing"
+
+func TestUnified_Equal(t *testing.T) {
+	if got := Unified("a", "b", "same\n", "same\n"); got != "" {
+		t.Errorf("expected empty diff, got:\n%s", got)
+	}
+}
+
+func TestUnified_SingleChange(t *testing.T) {
+	a := "---\nname: Brand Voice\ndescription: d\n---\nbody\n"
+	b := "---\nname: brand-voice\ndescription: d\n---\nbody\n"
+
+	want := `--- a/SKILL.md
++++ b/SKILL.md
+@@ -1,5 +1,5 @@
+ ---
+-name: Brand Voice
++name: brand-voice
+ description: d
+ ---
+ body
+`
+	if got := Unified("a/SKILL.md", "b/SKILL.md", a, b); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_SeparateHunks(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	b := "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n"
+
+	want := `--- a
++++ b
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -7,4 +7,4 @@
+ 7
+ 8
+ 9
+-10
++ten
+`
+	if got := Unified("a", "b", a, b); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_MissingNewline(t *testing.T) {
+	want := `--- a
++++ b
+@@ -1,1 +1,1 @@
+-end
+\ No newline at end of file
++end
+`
+	if got := Unified("a", "b", "end", "end\n"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
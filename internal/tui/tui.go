@@ -10,10 +10,13 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/manzanita-research/chaparral/internal/budget"
 	"github.com/manzanita-research/chaparral/internal/config"
 	"github.com/manzanita-research/chaparral/internal/discovery"
 	"github.com/manzanita-research/chaparral/internal/linker"
 	"github.com/manzanita-research/chaparral/internal/marketplace"
+	"github.com/manzanita-research/chaparral/internal/mcp"
+	"github.com/manzanita-research/chaparral/internal/render"
 )
 
 const maxContentWidth = 80
@@ -41,6 +44,9 @@ type Model struct {
 	basePath string
 	orgs     []config.Org
 	statuses map[string][]linker.LinkStatus // keyed by org name
+	mcp      map[string][]mcp.ServerStatus  // keyed by org name
+	rendered map[string][]render.FileStatus // keyed by org name
+	budgets  map[string]budget.Report       // keyed by org name
 	results  []linker.LinkResult
 	cursor     int
 	repoCursor int
@@ -71,6 +77,9 @@ type Model struct {
 type orgsLoaded struct {
 	orgs     []config.Org
 	statuses map[string][]linker.LinkStatus
+	mcp      map[string][]mcp.ServerStatus
+	rendered map[string][]render.FileStatus
+	budgets  map[string]budget.Report
 	err      error
 }
 
@@ -114,12 +123,18 @@ func (m Model) Init() tea.Cmd {
 			}
 
 			statuses := make(map[string][]linker.LinkStatus)
+			mcpStatuses := make(map[string][]mcp.ServerStatus)
+			rendered := make(map[string][]render.FileStatus)
+			budgets := make(map[string]budget.Report)
 			for _, org := range orgs {
 				st, _ := linker.StatusOrg(org)
 				statuses[org.Name] = st
+				mcpStatuses[org.Name] = mcp.StatusOrg(org)
+				rendered[org.Name], _ = render.StatusOrg(org)
+				budgets[org.Name], _ = budget.ReportOrg(org)
 			}
 
-			return orgsLoaded{orgs: orgs, statuses: statuses}
+			return orgsLoaded{orgs: orgs, statuses: statuses, mcp: mcpStatuses, rendered: rendered, budgets: budgets}
 		},
 		func() tea.Msg {
 			installed, err := marketplace.ScanInstalled()
@@ -161,6 +176,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.err = msg.err
 		m.orgs = msg.orgs
 		m.statuses = msg.statuses
+		m.mcp = msg.mcp
+		m.rendered = msg.rendered
+		m.budgets = msg.budgets
 		m.view = viewDashboard
 
 	case pluginsLoaded:
@@ -513,9 +531,13 @@ func (m Model) renderSkillsTab(b *strings.Builder, org config.Org, statuses []li
 	for _, skill := range skillNames {
 		repos := skillRepos[skill]
 		linked := 0
-		total := len(repos)
+		total := 0
 		for _, r := range repos {
-			if r.State == "linked" {
+			if r.State == "skipped" {
+				continue
+			}
+			total++
+			if r.IsLinked() {
 				linked++
 			}
 		}
@@ -527,10 +549,17 @@ func (m Model) renderSkillsTab(b *strings.Builder, org config.Org, statuses []li
 			icon = statusStale
 		}
 
+		detail := fmt.Sprintf("(%d/%d repos)", linked, total)
+		for _, size := range m.budgets[org.Name].Skills {
+			if size.Skill == skill {
+				detail = fmt.Sprintf("(%d/%d repos, ~%d tokens)", linked, total, size.MetadataTokens)
+			}
+		}
+
 		b.WriteString(fmt.Sprintf("    %s %s %s\n",
 			icon,
 			repoStyle.Render(skill),
-			dimStyle.Render(fmt.Sprintf("(%d/%d repos)", linked, total)),
+			dimStyle.Render(detail),
 		))
 	}
 
@@ -581,8 +610,13 @@ func (m Model) renderReposTab(b *strings.Builder, org config.Org, statuses []lin
 	for ri, repo := range repoOrder {
 		skills := repoSkills[repo]
 		linked := 0
+		total := 0
 		for _, s := range skills {
-			if s.State == "linked" {
+			if s.State == "skipped" {
+				continue
+			}
+			total++
+			if s.IsLinked() {
 				linked++
 			}
 		}
@@ -592,15 +626,73 @@ func (m Model) renderReposTab(b *strings.Builder, org config.Org, statuses []lin
 			repoCursor = "  " + lipgloss.NewStyle().Foreground(colorTerracotta).Render("> ")
 		}
 
+		detail := dimStyle.Render(fmt.Sprintf("(%d/%d skills)", linked, total))
+		report := m.budgets[org.Name]
+		for _, usage := range report.Repos {
+			if usage.Repo != repo {
+				continue
+			}
+			tokens := fmt.Sprintf("~%d tokens", usage.MetadataTokens)
+			if usage.OverBudget {
+				detail += " " + skillStale.Render(fmt.Sprintf("%s, over budget of %d", tokens, report.Budget))
+			} else {
+				detail += " " + dimStyle.Render(tokens)
+			}
+		}
+
 		b.WriteString(fmt.Sprintf("%s%s %s\n",
 			repoCursor,
 			repoStyle.Render(repo),
-			dimStyle.Render(fmt.Sprintf("(%d/%d skills)", linked, len(skills))),
+			detail,
 		))
 
 		for _, s := range skills {
 			icon := statusIcon(s.State)
-			b.WriteString(fmt.Sprintf("      %s %s\n", icon, dimStyle.Render(s.Skill)))
+			name := s.Skill
+			if s.State == "linked+overlay" {
+				name += " +overlay"
+			}
+			if s.State == "skipped" {
+				icon = dimStyle.Render("-")
+				name += " (" + s.Detail + ")"
+			}
+			b.WriteString(fmt.Sprintf("      %s %s\n", icon, dimStyle.Render(name)))
+		}
+
+		// Show rendered templates for this repo
+		var files []render.FileStatus
+		for _, fs := range m.rendered[org.Name] {
+			if fs.Repo == repo {
+				files = append(files, fs)
+			}
+		}
+		if len(files) > 0 {
+			b.WriteString("      " + dimStyle.Render("templates") + "\n")
+			for _, fs := range files {
+				b.WriteString(fmt.Sprintf("        %s %s %s\n",
+					statusIcon(fs.State),
+					dimStyle.Render(fs.Path),
+					dimStyle.Render("("+fs.State+")"),
+				))
+			}
+		}
+
+		// Show managed MCP servers for this repo
+		var servers []mcp.ServerStatus
+		for _, ms := range m.mcp[org.Name] {
+			if ms.Repo == repo || ms.Repo == "(org)" {
+				servers = append(servers, ms)
+			}
+		}
+		if len(servers) > 0 {
+			b.WriteString("      " + dimStyle.Render("mcp") + "\n")
+			for _, ms := range servers {
+				b.WriteString(fmt.Sprintf("        %s %s %s\n",
+					statusIcon(ms.State),
+					dimStyle.Render(ms.Server),
+					dimStyle.Render("("+ms.State+")"),
+				))
+			}
 		}
 
 		// Show plugins for this repo
@@ -853,13 +945,13 @@ func (m Model) doInstall(pluginID, repoPath string) tea.Cmd {
 
 func statusIcon(state string) string {
 	switch state {
-	case "linked", "created", "exists":
+	case "linked", "linked+overlay", "created", "exists", "present", "rendered":
 		return statusLinked
 	case "missing", "error", "removed":
 		return statusMissing
-	case "stale", "skipped", "updated":
+	case "stale", "skipped", "updated", "drifted", "outdated":
 		return statusStale
-	case "conflict":
+	case "conflict", "invalid":
 		return skillMissing.Render("✕")
 	default:
 		return statusMissing
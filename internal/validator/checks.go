@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/manzanita-research/chaparral/internal/skillmeta"
+)
+
+const (
+	maxDescriptionLength = 1024
+	maxBodyLines         = 500
+)
+
+// knownTools are the tool names Claude Code accepts in allowed-tools.
+var knownTools = map[string]bool{
+	"Agent":        true,
+	"Bash":         true,
+	"BashOutput":   true,
+	"Edit":         true,
+	"ExitPlanMode": true,
+	"Glob":         true,
+	"Grep":         true,
+	"KillShell":    true,
+	"LS":           true,
+	"MultiEdit":    true,
+	"NotebookEdit": true,
+	"NotebookRead": true,
+	"Read":         true,
+	"Skill":        true,
+	"SlashCommand": true,
+	"Task":         true,
+	"TodoWrite":    true,
+	"WebFetch":     true,
+	"WebSearch":    true,
+	"Write":        true,
+}
+
+// triggerRe matches the phrasing Claude uses to decide when to load a skill.
+var triggerRe = regexp.MustCompile(`(?i)\b(use (it |this( skill)? )?(when|for|to|after|before|whenever)|when (the user|you|asked|working|writing|reviewing))\b`)
+
+// checkDescription applies the description length and trigger phrasing rules.
+func checkDescription(r *ValidationResult, opts Options, description string) {
+	if description == "" {
+		return
+	}
+	if n := utf8.RuneCountInString(description); n > maxDescriptionLength {
+		r.report(opts, "description-length",
+			fmt.Sprintf("description is %d characters (max %d)", n, maxDescriptionLength))
+	}
+	if !triggerRe.MatchString(description) {
+		r.report(opts, "description-trigger",
+			`description doesn't say when to use the skill (e.g. "Use when ...")`)
+	}
+}
+
+// checkAllowedTools flags allowed-tools entries that aren't tool names Claude
+// Code knows. Entries may carry a pattern, as in Bash(git log:*), and MCP
+// tools (mcp__server__tool) are always accepted.
+func checkAllowedTools(r *ValidationResult, opts Options, tools []string) {
+	for _, tool := range tools {
+		name := tool
+		if i := strings.Index(name, "("); i >= 0 {
+			name = name[:i]
+		}
+		name = strings.TrimSpace(name)
+		if strings.HasPrefix(name, "mcp__") || knownTools[name] {
+			continue
+		}
+		r.report(opts, "allowed-tools", fmt.Sprintf("allowed-tools: unknown tool %q", tool))
+	}
+}
+
+// checkBody applies the rules that look at the markdown body of SKILL.md.
+func checkBody(r *ValidationResult, opts Options, body string) {
+	if n := strings.Count(strings.TrimRight(body, "\n"), "\n") + 1; body != "" && n > maxBodyLines {
+		r.report(opts, "body-length",
+			fmt.Sprintf("SKILL.md body is %d lines (max %d) — move detail into reference files", n, maxBodyLines))
+	}
+}
+
+// checkRequiredFields reports required fields the frontmatter doesn't set.
+// name and description are already covered by their own rules.
+func checkRequiredFields(r *ValidationResult, opts Options, fields map[string]any) {
+	for _, key := range opts.RequiredFields {
+		if key == "name" || key == "description" {
+			continue
+		}
+		if v, ok := fields[key]; !ok || v == nil || v == "" {
+			r.report(opts, "required-field", fmt.Sprintf("missing required field: %s", key))
+		}
+	}
+}
+
+// checkBannedWords reports each banned word or phrase found in SKILL.md,
+// with the line it first appears on. Matching ignores case and respects word
+// boundaries, so "simply" doesn't match "simplify".
+func checkBannedWords(r *ValidationResult, opts Options, content string) {
+	lines := strings.Split(content, "\n")
+	for _, word := range opts.BannedWords {
+		re, err := regexp.Compile(`(?i)\b` + regexp.QuoteMeta(word) + `\b`)
+		if err != nil {
+			continue
+		}
+		for i, line := range lines {
+			if re.MatchString(line) {
+				r.report(opts, "banned-word", fmt.Sprintf("uses banned word %q (line %d)", word, i+1))
+				break
+			}
+		}
+	}
+}
+
+// skillBody returns the markdown after the frontmatter.
+func skillBody(path string, fm skillmeta.Frontmatter) string {
+	data, err := os.ReadFile(path)
+	if err != nil || fm.BodyOffset > len(data) {
+		return ""
+	}
+	return string(data[fm.BodyOffset:])
+}
@@ -0,0 +1,183 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+	"github.com/manzanita-research/chaparral/internal/render"
+)
+
+// defaultClaudeMDBudget is the size the org CLAUDE.md may grow to before
+// it's flagged. Every byte is loaded into every session in every repo.
+const defaultClaudeMDBudget = 40000
+
+// maxImportDepth matches how many hops of @imports Claude Code follows.
+const maxImportDepth = 5
+
+// orgOwnedMarker marks a section of the org CLAUDE.md that repos mustn't
+// redefine. It goes on the heading line or the line right after it.
+const orgOwnedMarker = "<!-- org-owned -->"
+
+// importRe matches @path imports: an @ at the start of a line or after
+// whitespace, followed by a path.
+var importRe = regexp.MustCompile(`(?:^|\s)@((?:~/|\.{0,2}/)?[A-Za-z0-9_.\-/]+)`)
+
+// headingRe matches an ATX markdown heading.
+var headingRe = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+
+// ValidateClaudeMD checks the org CLAUDE.md, when the manifest names one,
+// using the rule settings from the manifest's validation section: that it
+// exists and has content, stays within the size budget, and that its
+// @imports resolve. Sibling repos' own CLAUDE.md files are checked for
+// redefining org-owned sections; those findings come back as separate results
+// named "<repo>/CLAUDE.md". Results are kept apart from ValidateOrg's so they
+// aren't mistaken for skills.
+func ValidateClaudeMD(org config.Org) ([]ValidationResult, error) {
+	if org.Manifest.ClaudeMD == "" {
+		return nil, nil
+	}
+	opts, err := OptionsFromConfig(org.Manifest.Validation)
+	if err != nil {
+		return nil, fmt.Errorf("validation config for %s: %w", org.Name, err)
+	}
+	return validateClaudeMD(org, opts), nil
+}
+
+func validateClaudeMD(org config.Org, opts Options) []ValidationResult {
+	result := ValidationResult{Skill: "CLAUDE.md"}
+	path := org.ClaudeMDPath()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		result.report(opts, "claude-md-exists", fmt.Sprintf("org CLAUDE.md not found at %s", org.Manifest.ClaudeMD))
+		return []ValidationResult{result}
+	}
+
+	if strings.TrimSpace(string(data)) == "" {
+		result.report(opts, "claude-md-empty", "org CLAUDE.md is empty")
+	}
+
+	budget := opts.ClaudeMDBudget
+	if budget <= 0 {
+		budget = defaultClaudeMDBudget
+	}
+	if len(data) > budget {
+		result.report(opts, "claude-md-size",
+			fmt.Sprintf("org CLAUDE.md is %d bytes (budget %d)", len(data), budget))
+	}
+
+	checkImports(&result, opts, path, string(data), 1, map[string]bool{path: true})
+
+	results := []ValidationResult{result}
+	owned := orgOwnedSections(string(data))
+	if len(owned) == 0 {
+		return results
+	}
+	for _, repo := range org.Repos {
+		if r := checkRepoClaudeMD(org, repo, owned, opts); len(r.Findings) > 0 {
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// checkImports reports @imports that don't resolve, following imported files
+// up to maxImportDepth hops. Imports inside code are ignored, as Claude Code
+// ignores them.
+func checkImports(r *ValidationResult, opts Options, path, content string, depth int, seen map[string]bool) {
+	prose, _ := splitFences(content)
+	for _, line := range prose {
+		line = inlineCodeRe.ReplaceAllString(line, "")
+		for _, m := range importRe.FindAllStringSubmatch(line, -1) {
+			target := strings.TrimRight(m[1], ".")
+			if !strings.ContainsAny(target, "./") {
+				continue // @mentions, not imports
+			}
+			resolved := resolveImport(path, target)
+
+			data, err := os.ReadFile(resolved)
+			if err != nil {
+				r.report(opts, "claude-md-imports",
+					fmt.Sprintf("%s imports missing file %q", filepath.Base(path), target))
+				continue
+			}
+			if depth < maxImportDepth && !seen[resolved] {
+				seen[resolved] = true
+				checkImports(r, opts, resolved, string(data), depth+1, seen)
+			}
+		}
+	}
+}
+
+// resolveImport turns an @import path into a file path. Relative paths are
+// relative to the importing file; ~/ is the home directory.
+func resolveImport(from, target string) string {
+	if strings.HasPrefix(target, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, target[2:])
+		}
+	}
+	if filepath.IsAbs(target) {
+		return target
+	}
+	return filepath.Join(filepath.Dir(from), filepath.FromSlash(target))
+}
+
+// orgOwnedSections returns the headings marked org-owned, normalised for
+// comparison.
+func orgOwnedSections(content string) map[string]string {
+	owned := make(map[string]string)
+	prose, _ := splitFences(content)
+	for i, line := range prose {
+		m := headingRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		marked := strings.Contains(line, orgOwnedMarker)
+		if !marked && i+1 < len(prose) && strings.TrimSpace(prose[i+1]) == orgOwnedMarker {
+			marked = true
+		}
+		if marked {
+			title := strings.TrimSpace(strings.ReplaceAll(m[1], orgOwnedMarker, ""))
+			owned[normalizeHeading(title)] = title
+		}
+	}
+	return owned
+}
+
+// checkRepoClaudeMD looks for org-owned headings in a repo's own CLAUDE.md
+// files. Files chaparral rendered from the brand repo are skipped.
+func checkRepoClaudeMD(org config.Org, repo string, owned map[string]string, opts Options) ValidationResult {
+	result := ValidationResult{Skill: repo + "/CLAUDE.md"}
+	for _, rel := range []string{"CLAUDE.md", filepath.Join(".claude", "CLAUDE.md")} {
+		path := filepath.Join(org.Path, repo, rel)
+		if info, err := os.Lstat(path); err != nil || info.Mode()&os.ModeSymlink != 0 {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || render.IsGenerated(data) {
+			continue
+		}
+
+		prose, _ := splitFences(string(data))
+		for _, line := range prose {
+			m := headingRe.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			if title, ok := owned[normalizeHeading(m[1])]; ok {
+				result.report(opts, "claude-md-org-sections",
+					fmt.Sprintf("%s redefines org-owned section %q", filepath.ToSlash(rel), title))
+			}
+		}
+	}
+	return result
+}
+
+func normalizeHeading(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
@@ -0,0 +1,134 @@
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+	"github.com/manzanita-research/chaparral/internal/discovery"
+	"github.com/manzanita-research/chaparral/internal/skillmeta"
+)
+
+// trailingSpaceRe matches whitespace at the end of a line.
+var trailingSpaceRe = regexp.MustCompile(`[ \t]+$`)
+
+// Fix is a set of safe, mechanical changes to one skill's SKILL.md.
+type Fix struct {
+	Skill   string
+	Path    string   // absolute path to SKILL.md
+	Changes []string // what was fixed, for display
+	Before  []byte
+	After   []byte
+}
+
+// Apply writes the fixed content back to SKILL.md.
+func (f Fix) Apply() error {
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(f.Path, f.After, info.Mode().Perm())
+}
+
+// FixSkill works out the safe fixes for a skill: setting the frontmatter name
+// to the directory name, adding the manifest's default license, and
+// normalising whitespace. Fixes for rules that are off are skipped. It
+// returns nil if there's nothing to fix.
+func FixSkill(skill config.Skill, opts Options) (*Fix, error) {
+	path := filepath.Join(skill.Path, "SKILL.md")
+	before, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't open %s: %w", path, err)
+	}
+
+	var changes []string
+	after := normalizeWhitespace(before)
+	if !bytes.Equal(after, before) {
+		changes = append(changes, "normalised whitespace")
+	}
+
+	fm, err := skillmeta.Parse(path, after)
+	if err != nil {
+		// Can't fix metadata we can't parse; keep the whitespace fixes
+		return newFix(skill, path, before, after, changes), nil
+	}
+
+	var fields []skillmeta.Field
+	nameRule := "name-matches-dir"
+	if fm.Name != "" && !kebabCaseRe.MatchString(fm.Name) {
+		nameRule = "name-format"
+	}
+	if fm.Name != skill.Name && kebabCaseRe.MatchString(skill.Name) && opts.severity(nameRule) != SeverityOff {
+		fields = append(fields, skillmeta.Field{Key: "name", Value: skill.Name})
+		changes = append(changes, fmt.Sprintf("set name to %q", skill.Name))
+	}
+	if fm.License == "" && opts.DefaultLicense != "" && opts.severity("license") != SeverityOff {
+		fields = append(fields, skillmeta.Field{Key: "license", Value: opts.DefaultLicense})
+		changes = append(changes, fmt.Sprintf("added license %s", opts.DefaultLicense))
+	}
+
+	if len(fields) > 0 {
+		if after, err = skillmeta.Set(path, after, fields...); err != nil {
+			return nil, err
+		}
+	}
+	return newFix(skill, path, before, after, changes), nil
+}
+
+func newFix(skill config.Skill, path string, before, after []byte, changes []string) *Fix {
+	if bytes.Equal(before, after) {
+		return nil
+	}
+	return &Fix{Skill: skill.Name, Path: path, Changes: changes, Before: before, After: after}
+}
+
+// FixOrg works out fixes for every skill in an org, using the manifest's
+// validation settings. Nothing is written; call Apply on each fix.
+func FixOrg(org config.Org) ([]Fix, error) {
+	opts, err := OptionsFromConfig(org.Manifest.Validation)
+	if err != nil {
+		return nil, fmt.Errorf("validation config for %s: %w", org.Name, err)
+	}
+
+	skills, err := discovery.FindSkills(org.SkillsPath())
+	if err != nil {
+		return nil, fmt.Errorf("finding skills in %s: %w", org.Name, err)
+	}
+
+	var fixes []Fix
+	for _, skill := range skills {
+		fix, err := FixSkill(skill, opts)
+		if err != nil {
+			return nil, err
+		}
+		if fix != nil {
+			fixes = append(fixes, *fix)
+		}
+	}
+	return fixes, nil
+}
+
+// normalizeWhitespace converts CRLF line endings to LF, strips trailing
+// whitespace, and ends the file with exactly one newline. Markdown hard line
+// breaks in the body (two trailing spaces after text) are kept.
+func normalizeWhitespace(data []byte) []byte {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+	inFrontmatter := len(lines) > 0 && strings.TrimSpace(lines[0]) == "---"
+	for i, line := range lines {
+		if i > 0 && inFrontmatter && strings.TrimSpace(line) == "---" {
+			inFrontmatter = false
+		}
+		hardBreak := strings.HasSuffix(line, "  ") && !strings.HasSuffix(line, "   ") && strings.TrimSpace(line) != ""
+		if hardBreak && !inFrontmatter {
+			continue
+		}
+		lines[i] = trailingSpaceRe.ReplaceAllString(line, "")
+	}
+	text = strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+	return []byte(text)
+}
@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+	"github.com/manzanita-research/chaparral/internal/similarity"
+	"github.com/manzanita-research/chaparral/internal/skillmeta"
+)
+
+// defaultOverlapThreshold is how similar two descriptions can be before
+// they're reported as ambiguous.
+const defaultOverlapThreshold = 0.75
+
+// checkOverlap compares every pair of skill descriptions in an org and warns
+// on both skills when they're so alike Claude could load the wrong one.
+// Pairs listed in opts.IgnoreOverlap are skipped. results must be in the same
+// order as skills.
+func checkOverlap(skills []config.Skill, results []ValidationResult, opts Options) {
+	if opts.severity("description-overlap") == SeverityOff {
+		return
+	}
+
+	threshold := opts.OverlapThreshold
+	if threshold <= 0 {
+		threshold = defaultOverlapThreshold
+	}
+
+	ignored := make(map[[2]string]bool)
+	for _, pair := range opts.IgnoreOverlap {
+		if len(pair) == 2 {
+			ignored[[2]string{pair[0], pair[1]}] = true
+			ignored[[2]string{pair[1], pair[0]}] = true
+		}
+	}
+
+	descriptions := make([]string, len(skills))
+	for i, skill := range skills {
+		fm, err := skillmeta.ParseFrontmatter(filepath.Join(skill.Path, "SKILL.md"))
+		if err == nil {
+			descriptions[i] = fm.Description
+		}
+	}
+
+	for i := range skills {
+		for j := i + 1; j < len(skills); j++ {
+			if descriptions[i] == "" || descriptions[j] == "" || ignored[[2]string{skills[i].Name, skills[j].Name}] {
+				continue
+			}
+			score := similarity.Cosine(descriptions[i], descriptions[j])
+			if score < threshold {
+				continue
+			}
+			results[i].report(opts, "description-overlap", overlapMessage(skills[j].Name, score))
+			results[j].report(opts, "description-overlap", overlapMessage(skills[i].Name, score))
+		}
+	}
+}
+
+func overlapMessage(other string, score float64) string {
+	return fmt.Sprintf("description overlaps with %q (%.0f%% similar); Claude may pick the wrong skill", other, score*100)
+}
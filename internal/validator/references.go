@@ -0,0 +1,247 @@
+package validator
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// markdownLinkRe matches inline links and images: [text](target "title").
+var markdownLinkRe = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+
+// codePathRe matches path-like tokens inside code: at least one slash, and
+// optionally prefixed with ./ or a skill-directory variable.
+var codePathRe = regexp.MustCompile(`(?:\$\{?[A-Za-z_]+\}?/|\{baseDir\}/|\./)?([A-Za-z0-9_.-]+(?:/[A-Za-z0-9_.-]+)+)`)
+
+// inlineCodeRe matches `inline code` spans.
+var inlineCodeRe = regexp.MustCompile("`([^`\n]+)`")
+
+// conventionalDirs are skill subdirectories a path in code can point into even
+// before they exist, so a missing scripts/ dir is still reported.
+var conventionalDirs = map[string]bool{
+	"assets":     true,
+	"examples":   true,
+	"references": true,
+	"scripts":    true,
+	"templates":  true,
+}
+
+// untrackedFiles never need a reference from SKILL.md. plugin.json is the
+// manifest chaparral publish writes into every skill.
+var untrackedFiles = []string{"SKILL.md", "README*", "LICENSE*", "CHANGELOG*", "plugin.json"}
+
+// checkReferences follows markdown links from SKILL.md (and from the markdown
+// files it links to) and paths mentioned in code, reporting references to
+// missing files or to files outside the skill. It then flags files nothing
+// references and scripts that aren't executable.
+func checkReferences(r *ValidationResult, opts Options, skillPath, body string) {
+	referenced := make(map[string]bool)
+	visited := map[string]bool{"SKILL.md": true}
+	queue := []struct{ file, content string }{{"SKILL.md", body}}
+
+	for len(queue) > 0 {
+		doc := queue[0]
+		queue = queue[1:]
+
+		prefix := ""
+		if doc.file != "SKILL.md" {
+			prefix = doc.file + " "
+		}
+
+		for _, target := range relativeLinks(doc.content) {
+			if strings.HasPrefix(target, "/") || filepath.IsAbs(target) {
+				r.report(opts, "reference-escape", fmt.Sprintf("%slinks to %q outside the skill directory", prefix, target))
+				continue
+			}
+			rel := path.Join(path.Dir(doc.file), target)
+			if rel == ".." || strings.HasPrefix(rel, "../") {
+				r.report(opts, "reference-escape", fmt.Sprintf("%slinks to %q outside the skill directory", prefix, target))
+				continue
+			}
+
+			info, err := os.Stat(filepath.Join(skillPath, filepath.FromSlash(rel)))
+			if err != nil {
+				r.report(opts, "missing-reference", fmt.Sprintf("%slinks to missing file %q", prefix, target))
+				continue
+			}
+			referenced[rel] = true
+
+			if !info.IsDir() && strings.HasSuffix(rel, ".md") && !visited[rel] {
+				visited[rel] = true
+				if data, err := os.ReadFile(filepath.Join(skillPath, filepath.FromSlash(rel))); err == nil {
+					queue = append(queue, struct{ file, content string }{rel, string(data)})
+				}
+			}
+		}
+
+		for _, p := range codePaths(doc.content, skillPath) {
+			if _, err := os.Stat(filepath.Join(skillPath, filepath.FromSlash(p))); err != nil {
+				r.report(opts, "missing-reference", fmt.Sprintf("%smentions missing file %q", prefix, p))
+				continue
+			}
+			referenced[p] = true
+		}
+	}
+
+	checkSkillFiles(r, opts, skillPath, referenced)
+}
+
+// checkSkillFiles walks the skill directory for files nothing references and
+// scripts without the executable bit. A file counts as a script when it lives
+// under scripts/ or starts with #!.
+func checkSkillFiles(r *ValidationResult, opts Options, skillPath string, referenced map[string]bool) {
+	filepath.WalkDir(skillPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == skillPath {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(skillPath, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if !isUntracked(rel) && !isReferenced(rel, referenced) {
+			r.report(opts, "unreferenced-file", fmt.Sprintf("%q isn't referenced from SKILL.md", rel))
+		}
+
+		if isScript(p, rel) {
+			if info, err := os.Stat(p); err == nil && info.Mode().Perm()&0111 == 0 {
+				r.report(opts, "script-executable", fmt.Sprintf("script %q isn't executable", rel))
+			}
+		}
+		return nil
+	})
+}
+
+// relativeLinks returns the local link targets in a markdown body, in order and
+// without duplicates. URLs, anchors and links inside code fences are skipped;
+// fragments and query strings are dropped from the targets.
+func relativeLinks(body string) []string {
+	seen := make(map[string]bool)
+	var links []string
+	prose, _ := splitFences(body)
+	for _, line := range prose {
+		for _, m := range markdownLinkRe.FindAllStringSubmatch(line, -1) {
+			target := m[1]
+			if strings.HasPrefix(target, "#") || strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") {
+				continue
+			}
+			if i := strings.IndexAny(target, "#?"); i >= 0 {
+				target = target[:i]
+			}
+			if unescaped, err := url.PathUnescape(target); err == nil {
+				target = unescaped
+			}
+			if target == "" || seen[target] {
+				continue
+			}
+			seen[target] = true
+			links = append(links, target)
+		}
+	}
+	return links
+}
+
+// codePaths returns skill-relative paths mentioned in code fences and inline
+// code. Only paths whose first segment is an entry in the skill directory, or
+// a conventional one like scripts/, count — anything else is probably a path
+// in the user's repo.
+func codePaths(body, skillPath string) []string {
+	prose, code := splitFences(body)
+	for _, line := range prose {
+		for _, m := range inlineCodeRe.FindAllStringSubmatch(line, -1) {
+			code = append(code, m[1])
+		}
+	}
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, line := range code {
+		for _, m := range codePathRe.FindAllStringSubmatch(line, -1) {
+			p := path.Clean(m[1])
+			first := strings.SplitN(p, "/", 2)[0]
+			if seen[p] || strings.HasPrefix(p, "../") || strings.Contains(m[0], "://") {
+				continue
+			}
+			if !conventionalDirs[first] {
+				if info, err := os.Stat(filepath.Join(skillPath, first)); err != nil || !info.IsDir() {
+					continue
+				}
+			}
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// splitFences separates a markdown body into prose lines and the lines inside
+// ``` or ~~~ code fences.
+func splitFences(body string) (prose, code []string) {
+	fence := ""
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				continue
+			}
+			code = append(code, line)
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		prose = append(prose, line)
+	}
+	return prose, code
+}
+
+// isReferenced reports whether rel or a directory containing it was referenced.
+func isReferenced(rel string, referenced map[string]bool) bool {
+	for p := rel; p != "." && p != "/"; p = path.Dir(p) {
+		if referenced[p] {
+			return true
+		}
+	}
+	return false
+}
+
+func isUntracked(rel string) bool {
+	for _, pattern := range untrackedFiles {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isScript(p, rel string) bool {
+	if strings.HasPrefix(rel, "scripts/") {
+		return true
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, 2)
+	n, _ := f.Read(head)
+	return n == 2 && string(head) == "#!"
+}
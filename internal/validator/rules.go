@@ -0,0 +1,163 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/manzanita-research/chaparral/internal/config"
+)
+
+// Severity says how a rule's findings are reported.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // blocks publishing; validate exits non-zero
+	SeverityWarning Severity = "warning" // reported but not blocking
+	SeverityOff     Severity = "off"     // rule doesn't run
+)
+
+// Rule is a single validation check. IDs are stable so they can be referenced
+// from configuration and tooling.
+type Rule struct {
+	ID       string
+	Severity Severity // default severity
+	Summary  string
+}
+
+// rules lists every check the validator knows about, with its default severity.
+var rules = []Rule{
+	{"skill-md", SeverityError, "skill directory has a SKILL.md"},
+	{"frontmatter", SeverityError, "SKILL.md frontmatter parses as YAML"},
+	{"name-required", SeverityError, "frontmatter has a name"},
+	{"description-required", SeverityError, "frontmatter has a description"},
+	{"name-format", SeverityError, "name is lowercase kebab-case"},
+	{"name-matches-dir", SeverityWarning, "name matches the skill directory"},
+	{"license", SeverityWarning, "frontmatter has a license"},
+	{"description-length", SeverityError, "description is at most 1024 characters"},
+	{"description-trigger", SeverityWarning, `description says when to use the skill ("Use when ...")`},
+	{"allowed-tools", SeverityWarning, "allowed-tools entries are known tool names"},
+	{"body-length", SeverityWarning, "SKILL.md body is at most 500 lines"},
+	{"missing-reference", SeverityWarning, "links and code paths in SKILL.md point at files that exist"},
+	{"reference-escape", SeverityError, "links stay inside the skill directory"},
+	{"unreferenced-file", SeverityWarning, "every file in the skill is referenced from SKILL.md"},
+	{"script-executable", SeverityWarning, "scripts are executable"},
+	{"required-field", SeverityError, "frontmatter sets the manifest's required fields"},
+	{"banned-word", SeverityError, "SKILL.md avoids the manifest's banned words"},
+	{"claude-md-exists", SeverityError, "the org CLAUDE.md exists"},
+	{"claude-md-empty", SeverityWarning, "the org CLAUDE.md has content"},
+	{"claude-md-size", SeverityWarning, "the org CLAUDE.md fits its size budget"},
+	{"claude-md-imports", SeverityError, "@imports in the org CLAUDE.md resolve"},
+	{"claude-md-org-sections", SeverityWarning, "repo CLAUDE.md files don't redefine org-owned sections"},
+	{"description-overlap", SeverityWarning, "no two skills have near-identical descriptions"},
+	{"requires-missing", SeverityError, "required skills exist in the org"},
+	{"requires-cycle", SeverityError, "required skills don't form a cycle"},
+}
+
+// Rules returns every rule with its default severity, sorted by ID.
+func Rules() []Rule {
+	sorted := make([]Rule, len(rules))
+	copy(sorted, rules)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+// lookupRule finds a rule by ID.
+func lookupRule(id string) (Rule, bool) {
+	for _, r := range rules {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Options adjusts how validation runs. The zero value uses every rule's
+// default severity.
+type Options struct {
+	Severities       map[string]Severity // rule ID → severity override
+	RequiredFields   []string            // extra frontmatter keys every skill must set
+	BannedWords      []string            // words and phrases SKILL.md must not use
+	DefaultLicense   string              // license FixSkill adds to skills without one
+	ClaudeMDBudget   int                 // max bytes for the org CLAUDE.md; 0 means the default
+	IgnoreOverlap    [][]string          // skill pairs allowed to have similar descriptions
+	OverlapThreshold float64             // description similarity that counts as overlap; 0 means the default
+}
+
+// OptionsFromConfig builds validation options from a manifest's validation
+// section, rejecting unknown rules and severities.
+func OptionsFromConfig(cfg config.ValidationConfig) (Options, error) {
+	opts := Options{
+		RequiredFields:   cfg.RequiredFields,
+		BannedWords:      cfg.BannedWords,
+		DefaultLicense:   cfg.DefaultLicense,
+		ClaudeMDBudget:   cfg.ClaudeMDBudget,
+		IgnoreOverlap:    cfg.IgnoreOverlap,
+		OverlapThreshold: cfg.OverlapThreshold,
+	}
+	if len(cfg.Rules) > 0 {
+		opts.Severities = make(map[string]Severity, len(cfg.Rules))
+		for id, s := range cfg.Rules {
+			opts.Severities[id] = Severity(s)
+		}
+	}
+	if err := opts.Validate(); err != nil {
+		return Options{}, err
+	}
+	for _, pair := range cfg.IgnoreOverlap {
+		if len(pair) != 2 {
+			return Options{}, fmt.Errorf("ignore_overlap entries must name exactly two skills, got %v", pair)
+		}
+	}
+	if cfg.OverlapThreshold < 0 || cfg.OverlapThreshold > 1 {
+		return Options{}, fmt.Errorf("overlap_threshold must be between 0 and 1, got %v", cfg.OverlapThreshold)
+	}
+	return opts, nil
+}
+
+// severity returns the effective severity for a rule.
+func (o Options) severity(id string) Severity {
+	if s, ok := o.Severities[id]; ok {
+		return s
+	}
+	if r, ok := lookupRule(id); ok {
+		return r.Severity
+	}
+	return SeverityError
+}
+
+// Validate checks that every override names a known rule and a real severity.
+func (o Options) Validate() error {
+	for id, s := range o.Severities {
+		if _, ok := lookupRule(id); !ok {
+			return fmt.Errorf("unknown validation rule %q", id)
+		}
+		switch s {
+		case SeverityError, SeverityWarning, SeverityOff:
+		default:
+			return fmt.Errorf("rule %s: severity must be error, warning or off, not %q", id, s)
+		}
+	}
+	return nil
+}
+
+// Finding is one problem reported by a rule.
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Message  string
+}
+
+// report records a finding under the rule's effective severity. Findings from
+// rules that are off are dropped.
+func (r *ValidationResult) report(opts Options, rule, msg string) {
+	switch opts.severity(rule) {
+	case SeverityOff:
+		return
+	case SeverityWarning:
+		r.Warnings = append(r.Warnings, msg)
+		r.Findings = append(r.Findings, Finding{Rule: rule, Severity: SeverityWarning, Message: msg})
+	default:
+		r.Errors = append(r.Errors, msg)
+		r.Findings = append(r.Findings, Finding{Rule: rule, Severity: SeverityError, Message: msg})
+	}
+}
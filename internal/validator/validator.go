@@ -1,10 +1,12 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 
 	"github.com/manzanita-research/chaparral/internal/config"
 	"github.com/manzanita-research/chaparral/internal/discovery"
@@ -14,10 +16,12 @@ import (
 var kebabCaseRe = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
 
 // ValidationResult holds errors and warnings for a single skill.
+// Findings carries the same problems tagged with the rule that raised them.
 type ValidationResult struct {
 	Skill    string
 	Errors   []string
 	Warnings []string
+	Findings []Finding
 }
 
 // IsValid returns true if no blocking errors were found.
@@ -25,53 +29,82 @@ func (r ValidationResult) IsValid() bool {
 	return len(r.Errors) == 0
 }
 
-// ValidateSkill checks a single skill for structure and metadata issues.
+// ValidateSkill checks a single skill using each rule's default severity.
 func ValidateSkill(skill config.Skill) ValidationResult {
+	return ValidateSkillWith(skill, Options{})
+}
+
+// ValidateSkillWith checks a single skill for structure and metadata issues.
+func ValidateSkillWith(skill config.Skill, opts Options) ValidationResult {
 	result := ValidationResult{Skill: skill.Name}
 
 	// Check SKILL.md exists
 	skillMDPath := filepath.Join(skill.Path, "SKILL.md")
 	if _, err := os.Stat(skillMDPath); os.IsNotExist(err) {
-		result.Errors = append(result.Errors, "missing SKILL.md")
+		result.report(opts, "skill-md", "missing SKILL.md")
 		return result
 	}
 
 	// Parse frontmatter
 	fm, err := skillmeta.ParseFrontmatter(skillMDPath)
 	if err != nil {
-		result.Errors = append(result.Errors, fmt.Sprintf("can't parse frontmatter: %v", err))
+		var perr *skillmeta.ParseError
+		if errors.As(err, &perr) && perr.Line > 0 {
+			result.report(opts, "frontmatter", fmt.Sprintf("can't parse frontmatter: SKILL.md line %d: %s", perr.Line, perr.Msg))
+		} else {
+			result.report(opts, "frontmatter", fmt.Sprintf("can't parse frontmatter: %v", err))
+		}
 		return result
 	}
 
 	// Required fields
 	if fm.Name == "" {
-		result.Errors = append(result.Errors, "missing required field: name")
+		result.report(opts, "name-required", "missing required field: name")
 	}
 	if fm.Description == "" {
-		result.Errors = append(result.Errors, "missing required field: description")
+		result.report(opts, "description-required", "missing required field: description")
 	}
 
 	// Name format (only check if name is present)
 	if fm.Name != "" && !kebabCaseRe.MatchString(fm.Name) {
-		result.Errors = append(result.Errors, fmt.Sprintf("name %q must be lowercase kebab-case (e.g., my-skill)", fm.Name))
+		result.report(opts, "name-format", fmt.Sprintf("name %q must be lowercase kebab-case (e.g., my-skill)", fm.Name))
 	}
 
 	// Name mismatch warning
 	if fm.Name != "" && fm.Name != skill.Name {
-		result.Warnings = append(result.Warnings,
+		result.report(opts, "name-matches-dir",
 			fmt.Sprintf("frontmatter name %q differs from directory name %q", fm.Name, skill.Name))
 	}
 
 	// License warning
 	if fm.License == "" {
-		result.Warnings = append(result.Warnings, "no license specified")
+		result.report(opts, "license", "no license specified")
+	}
+
+	checkRequiredFields(&result, opts, fm.Fields)
+	checkDescription(&result, opts, fm.Description)
+	checkAllowedTools(&result, opts, fm.AllowedTools)
+	body := skillBody(skillMDPath, fm)
+	checkBody(&result, opts, body)
+	checkReferences(&result, opts, skill.Path, body)
+	if len(opts.BannedWords) > 0 {
+		if data, err := os.ReadFile(skillMDPath); err == nil {
+			checkBannedWords(&result, opts, string(data))
+		}
 	}
 
 	return result
 }
 
-// ValidateOrg validates all skills in an org.
+// ValidateOrg validates all skills in an org, using the rule settings from the
+// manifest's validation section. The org CLAUDE.md is checked separately by
+// ValidateClaudeMD.
 func ValidateOrg(org config.Org) ([]ValidationResult, error) {
+	opts, err := OptionsFromConfig(org.Manifest.Validation)
+	if err != nil {
+		return nil, fmt.Errorf("validation config for %s: %w", org.Name, err)
+	}
+
 	skills, err := discovery.FindSkills(org.SkillsPath())
 	if err != nil {
 		return nil, fmt.Errorf("finding skills in %s: %w", org.Name, err)
@@ -79,7 +112,59 @@ func ValidateOrg(org config.Org) ([]ValidationResult, error) {
 
 	var results []ValidationResult
 	for _, skill := range skills {
-		results = append(results, ValidateSkill(skill))
+		results = append(results, ValidateSkillWith(skill, opts))
 	}
+
+	checkDependencies(skills, results, opts)
+	checkOverlap(skills, results, opts)
 	return results, nil
 }
+
+// checkDependencies flags skills whose "requires" names a skill that doesn't
+// exist in the org, or that depend on themselves through a cycle.
+// results must be in the same order as skills.
+func checkDependencies(skills []config.Skill, results []ValidationResult, opts Options) {
+	requires := make(map[string][]string)
+	for _, skill := range skills {
+		fm, err := skillmeta.ParseFrontmatter(filepath.Join(skill.Path, "SKILL.md"))
+		if err != nil {
+			continue
+		}
+		requires[skill.Name] = fm.Requires
+	}
+
+	for i, skill := range skills {
+		for _, dep := range requires[skill.Name] {
+			if _, ok := requires[dep]; !ok {
+				results[i].report(opts, "requires-missing", fmt.Sprintf("requires missing skill %q", dep))
+			}
+		}
+		if cycle := findCycle(skill.Name, requires); cycle != nil {
+			results[i].report(opts, "requires-cycle",
+				fmt.Sprintf("dependency cycle: %s", strings.Join(cycle, " -> ")))
+		}
+	}
+}
+
+// findCycle returns the path of a dependency cycle that leads back to start,
+// or nil if start isn't part of one.
+func findCycle(start string, requires map[string][]string) []string {
+	visited := make(map[string]bool)
+	var walk func(name string, path []string) []string
+	walk = func(name string, path []string) []string {
+		for _, dep := range requires[name] {
+			if dep == start {
+				return append(path, dep)
+			}
+			if visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			if cycle := walk(dep, append(path, dep)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return walk(start, []string{start})
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/manzanita-research/chaparral/internal/config"
@@ -24,7 +25,7 @@ func makeSkill(t *testing.T, dir, name, content string, writeFile bool) config.S
 
 func TestValidateSkill_Valid(t *testing.T) {
 	dir := t.TempDir()
-	skill := makeSkill(t, dir, "brand-voice", "---\nname: brand-voice\ndescription: Write in the Manzanita Research voice\nlicense: MIT\n---\n", true)
+	skill := makeSkill(t, dir, "brand-voice", "---\nname: brand-voice\ndescription: Write in the Manzanita Research voice. Use when writing copy.\nlicense: MIT\n---\n", true)
 
 	result := ValidateSkill(skill)
 	if !result.IsValid() {
@@ -109,6 +110,62 @@ func TestValidateSkill_MissingSKILLmd(t *testing.T) {
 	}
 }
 
+// makeOrg creates an org whose brand repo holds the given SKILL.md contents.
+func makeOrg(t *testing.T, skills map[string]string) config.Org {
+	t.Helper()
+	dir := t.TempDir()
+	skillsDir := filepath.Join(dir, "brand", "skills")
+	for name, content := range skills {
+		makeSkill(t, skillsDir, name, content, true)
+	}
+	return config.Org{
+		Name:      "test-org",
+		Path:      dir,
+		BrandRepo: "brand",
+		Manifest:  config.Manifest{Org: "test-org", SkillsDir: "skills"},
+	}
+}
+
+func findResult(t *testing.T, results []ValidationResult, skill string) ValidationResult {
+	t.Helper()
+	for _, r := range results {
+		if r.Skill == skill {
+			return r
+		}
+	}
+	t.Fatalf("no result for %s", skill)
+	return ValidationResult{}
+}
+
+func TestValidateOrg_MissingDependency(t *testing.T) {
+	org := makeOrg(t, map[string]string{
+		"release-notes": "---\nname: release-notes\ndescription: notes\nlicense: MIT\nrequires: [brand-voice]\n---\n",
+	})
+
+	results, err := ValidateOrg(org)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertHasError(t, findResult(t, results, "release-notes"), `requires missing skill "brand-voice"`)
+}
+
+func TestValidateOrg_DependencyCycle(t *testing.T) {
+	org := makeOrg(t, map[string]string{
+		"a-skill": "---\nname: a-skill\ndescription: a\nlicense: MIT\nrequires: [b-skill]\n---\n",
+		"b-skill": "---\nname: b-skill\ndescription: b\nlicense: MIT\nrequires: [a-skill]\n---\n",
+		"c-skill": "---\nname: c-skill\ndescription: c\nlicense: MIT\nrequires: [a-skill]\n---\n",
+	})
+
+	results, err := ValidateOrg(org)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertHasError(t, findResult(t, results, "a-skill"), "dependency cycle: a-skill -> b-skill -> a-skill")
+	if r := findResult(t, results, "c-skill"); !r.IsValid() {
+		t.Errorf("c-skill depends on a cycle but isn't in one, got errors: %v", r.Errors)
+	}
+}
+
 func assertHasError(t *testing.T, result ValidationResult, msg string) {
 	t.Helper()
 	for _, e := range result.Errors {
@@ -128,3 +185,410 @@ func assertHasWarning(t *testing.T, result ValidationResult, msg string) {
 	}
 	t.Errorf("expected warning %q in %v", msg, result.Warnings)
 }
+
+func TestValidateSkill_FrontmatterSyntaxError(t *testing.T) {
+	dir := t.TempDir()
+	skill := makeSkill(t, dir, "brand-voice", "---\nname: brand-voice\ndescription: [unclosed\nlicense: MIT\n---\n", true)
+
+	result := ValidateSkill(skill)
+	if result.IsValid() {
+		t.Fatal("expected error for invalid YAML")
+	}
+	if !strings.Contains(result.Errors[0], "SKILL.md line ") {
+		t.Errorf("expected a line-numbered error, got %q", result.Errors[0])
+	}
+}
+
+func findFinding(result ValidationResult, rule string) (Finding, bool) {
+	for _, f := range result.Findings {
+		if f.Rule == rule {
+			return f, true
+		}
+	}
+	return Finding{}, false
+}
+
+func TestValidateSkill_DescriptionTooLong(t *testing.T) {
+	dir := t.TempDir()
+	desc := strings.Repeat("a", 1025)
+	skill := makeSkill(t, dir, "brand-voice", "---\nname: brand-voice\ndescription: "+desc+"\nlicense: MIT\n---\n", true)
+
+	result := ValidateSkill(skill)
+	assertHasError(t, result, "description is 1025 characters (max 1024)")
+	if f, ok := findFinding(result, "description-length"); !ok || f.Severity != SeverityError {
+		t.Errorf("expected description-length error finding, got %v", result.Findings)
+	}
+}
+
+func TestValidateSkill_DescriptionTrigger(t *testing.T) {
+	dir := t.TempDir()
+	skill := makeSkill(t, dir, "brand-voice", "---\nname: brand-voice\ndescription: Brand voice guide\nlicense: MIT\n---\n", true)
+
+	// A warning by default
+	result := ValidateSkill(skill)
+	if f, ok := findFinding(result, "description-trigger"); !ok || f.Severity != SeverityWarning {
+		t.Errorf("expected description-trigger warning, got %v", result.Findings)
+	}
+
+	off := Options{Severities: map[string]Severity{"description-trigger": SeverityOff}}
+	if _, ok := findFinding(ValidateSkillWith(skill, off), "description-trigger"); ok {
+		t.Error("description-trigger should be off when configured off")
+	}
+
+	opts := Options{}
+
+	skill = makeSkill(t, dir, "brand-voice", "---\nname: brand-voice\ndescription: Brand voice guide. Use when writing copy.\nlicense: MIT\n---\n", true)
+	if _, ok := findFinding(ValidateSkillWith(skill, opts), "description-trigger"); ok {
+		t.Error("description with \"Use when\" shouldn't be flagged")
+	}
+}
+
+func TestValidateSkill_AllowedTools(t *testing.T) {
+	dir := t.TempDir()
+	content := "---\nname: committer\ndescription: Commit changes. Use when committing.\nlicense: MIT\n" +
+		"allowed-tools: [Read, Bash(git commit:*), mcp__github__create_pr, Bsh]\n---\n"
+	skill := makeSkill(t, dir, "committer", content, true)
+
+	result := ValidateSkill(skill)
+	assertHasWarning(t, result, `allowed-tools: unknown tool "Bsh"`)
+	if len(result.Warnings) != 1 {
+		t.Errorf("expected only the Bsh warning, got %v", result.Warnings)
+	}
+}
+
+func TestValidateSkill_BodyLength(t *testing.T) {
+	dir := t.TempDir()
+	body := strings.Repeat("line\n", 501)
+	skill := makeSkill(t, dir, "brand-voice", "---\nname: brand-voice\ndescription: d\nlicense: MIT\n---\n"+body, true)
+
+	assertHasWarning(t, ValidateSkill(skill), "SKILL.md body is 501 lines (max 500) — move detail into reference files")
+}
+
+func TestValidateSkill_MissingReference(t *testing.T) {
+	dir := t.TempDir()
+	body := "See [the guide](references/guide.md#tone) and [examples](examples.md).\n" +
+		"[Docs](https://example.com/docs.md) and [top](#top) are fine.\n" +
+		"```\n[not a link](nowhere.md)\n```\n"
+	skill := makeSkill(t, dir, "brand-voice", "---\nname: brand-voice\ndescription: Use when testing\nlicense: MIT\n---\n"+body, true)
+	if err := os.MkdirAll(filepath.Join(skill.Path, "references"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skill.Path, "references", "guide.md"), []byte("guide\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := ValidateSkill(skill)
+	assertHasWarning(t, result, `links to missing file "examples.md"`)
+	if len(result.Errors) != 0 || len(result.Warnings) != 1 {
+		t.Errorf("expected only the examples.md warning, got errors %v warnings %v", result.Errors, result.Warnings)
+	}
+}
+
+func TestValidateSkillWith_Severities(t *testing.T) {
+	dir := t.TempDir()
+	skill := makeSkill(t, dir, "brand-voice", "---\nname: brand-voice\ndescription: Use when testing\n---\n", true)
+
+	result := ValidateSkillWith(skill, Options{Severities: map[string]Severity{"license": SeverityError}})
+	assertHasError(t, result, "no license specified")
+
+	result = ValidateSkillWith(skill, Options{Severities: map[string]Severity{"license": SeverityOff}})
+	if len(result.Warnings) != 0 || len(result.Errors) != 0 {
+		t.Errorf("license off should report nothing, got errors %v warnings %v", result.Errors, result.Warnings)
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	if err := (Options{Severities: map[string]Severity{"license": SeverityError}}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (Options{Severities: map[string]Severity{"no-such-rule": SeverityError}}).Validate(); err == nil {
+		t.Error("expected error for unknown rule")
+	}
+	if err := (Options{Severities: map[string]Severity{"license": "fatal"}}).Validate(); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+}
+
+// writeSkillFile writes a file inside a skill directory, creating parents.
+func writeSkillFile(t *testing.T, skill config.Skill, rel, content string, mode os.FileMode) {
+	t.Helper()
+	path := filepath.Join(skill.Path, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateSkill_CodeFencePaths(t *testing.T) {
+	dir := t.TempDir()
+	body := "Run the linter:\n\n```bash\n./scripts/lint.sh --fix\nbash scripts/missing.sh\ncat src/main.go\n```\n"
+	skill := makeSkill(t, dir, "linter", "---\nname: linter\ndescription: Use when linting\nlicense: MIT\n---\n"+body, true)
+	writeSkillFile(t, skill, "scripts/lint.sh", "#!/bin/sh\n", 0755)
+
+	result := ValidateSkill(skill)
+	assertHasWarning(t, result, `mentions missing file "scripts/missing.sh"`)
+	if len(result.Warnings) != 1 {
+		t.Errorf("src/main.go is a repo path and lint.sh is referenced and executable; got warnings %v", result.Warnings)
+	}
+}
+
+func TestValidateSkill_ReferenceEscape(t *testing.T) {
+	dir := t.TempDir()
+	body := "See [shared](../other-skill/SKILL.md) and [abs](/etc/passwd).\n"
+	skill := makeSkill(t, dir, "brand-voice", "---\nname: brand-voice\ndescription: d\nlicense: MIT\n---\n"+body, true)
+	makeSkill(t, dir, "other-skill", "---\nname: other-skill\n---\n", true)
+
+	result := ValidateSkill(skill)
+	assertHasError(t, result, `links to "../other-skill/SKILL.md" outside the skill directory`)
+	assertHasError(t, result, `links to "/etc/passwd" outside the skill directory`)
+}
+
+func TestValidateSkill_NestedReferences(t *testing.T) {
+	dir := t.TempDir()
+	body := "Read [the guide](references/guide.md).\n"
+	skill := makeSkill(t, dir, "brand-voice", "---\nname: brand-voice\ndescription: Use when testing\nlicense: MIT\n---\n"+body, true)
+	writeSkillFile(t, skill, "references/guide.md", "See [tone](tone.md) and [gone](gone.md).\n", 0644)
+	writeSkillFile(t, skill, "references/tone.md", "tone\n", 0644)
+
+	result := ValidateSkill(skill)
+	assertHasWarning(t, result, `references/guide.md links to missing file "gone.md"`)
+	if len(result.Warnings) != 1 {
+		t.Errorf("tone.md is reachable through guide.md, got warnings %v", result.Warnings)
+	}
+}
+
+func TestValidateSkill_UnreferencedAndScripts(t *testing.T) {
+	dir := t.TempDir()
+	body := "Run `scripts/build.sh` first.\n"
+	skill := makeSkill(t, dir, "builder", "---\nname: builder\ndescription: Use when building\nlicense: MIT\n---\n"+body, true)
+	writeSkillFile(t, skill, "scripts/build.sh", "#!/bin/sh\n", 0644)
+	writeSkillFile(t, skill, "references/old.md", "old\n", 0644)
+	writeSkillFile(t, skill, "LICENSE", "MIT\n", 0644)
+	writeSkillFile(t, skill, "plugin.json", "{}\n", 0644)
+	writeSkillFile(t, skill, ".claude-plugin/plugin.json", "{}\n", 0644)
+
+	result := ValidateSkill(skill)
+	assertHasWarning(t, result, `"references/old.md" isn't referenced from SKILL.md`)
+	assertHasWarning(t, result, `script "scripts/build.sh" isn't executable`)
+	if len(result.Warnings) != 2 {
+		t.Errorf("expected exactly two warnings, got %v", result.Warnings)
+	}
+}
+
+func TestValidateOrg_ManifestRules(t *testing.T) {
+	org := makeOrg(t, map[string]string{
+		"brand-voice": "---\nname: brand-voice\ndescription: Simply write well\n---\n# Voice\n\nJust be clear.\n",
+	})
+	org.Manifest.Validation = config.ValidationConfig{
+		Rules:          map[string]string{"license": "error"},
+		RequiredFields: []string{"owner", "description"},
+		BannedWords:    []string{"simply", "just"},
+	}
+
+	results, err := ValidateOrg(org)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := findResult(t, results, "brand-voice")
+	assertHasError(t, r, "no license specified")
+	assertHasError(t, r, "missing required field: owner")
+	assertHasError(t, r, `uses banned word "simply" (line 3)`)
+	assertHasError(t, r, `uses banned word "just" (line 7)`)
+	if len(r.Errors) != 4 {
+		t.Errorf("expected 4 errors, got %v", r.Errors)
+	}
+}
+
+func TestValidateOrg_BadManifestRules(t *testing.T) {
+	org := makeOrg(t, map[string]string{
+		"brand-voice": "---\nname: brand-voice\ndescription: d\n---\n",
+	})
+	org.Manifest.Validation.Rules = map[string]string{"licence": "error"}
+
+	if _, err := ValidateOrg(org); err == nil {
+		t.Error("expected error for unknown rule ID")
+	}
+}
+
+func TestFixSkill(t *testing.T) {
+	dir := t.TempDir()
+	content := "---\nname: Brand Voice # display name\ndescription: Use when testing  \n---\n# Voice\r\nLine one  \nLine two\t\n\n\n"
+	skill := makeSkill(t, dir, "brand-voice", content, true)
+
+	fix, err := FixSkill(skill, Options{DefaultLicense: "MIT"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fix == nil {
+		t.Fatal("expected a fix")
+	}
+
+	want := "---\nname: brand-voice # display name\ndescription: Use when testing\nlicense: MIT\n---\n# Voice\nLine one  \nLine two\n"
+	if string(fix.After) != want {
+		t.Errorf("got:\n%q\nwant:\n%q", fix.After, want)
+	}
+	if len(fix.Changes) != 3 {
+		t.Errorf("expected 3 changes, got %v", fix.Changes)
+	}
+
+	// Nothing is written until Apply
+	data, _ := os.ReadFile(fix.Path)
+	if string(data) != content {
+		t.Error("FixSkill must not write the file")
+	}
+	if err := fix.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	result := ValidateSkill(skill)
+	if !result.IsValid() || len(result.Warnings) != 0 {
+		t.Errorf("expected a clean skill after fixing, got errors %v warnings %v", result.Errors, result.Warnings)
+	}
+
+	fix, err = FixSkill(skill, Options{DefaultLicense: "MIT"})
+	if err != nil || fix != nil {
+		t.Errorf("expected nothing left to fix, got %v, %v", fix, err)
+	}
+}
+
+func TestFixSkill_RespectsRulesOff(t *testing.T) {
+	dir := t.TempDir()
+	skill := makeSkill(t, dir, "brand-voice", "---\nname: voice\ndescription: d\n---\n", true)
+
+	opts := Options{
+		DefaultLicense: "MIT",
+		Severities:     map[string]Severity{"license": SeverityOff, "name-matches-dir": SeverityOff},
+	}
+	fix, err := FixSkill(skill, opts)
+	if err != nil || fix != nil {
+		t.Errorf("expected no fix when the rules are off, got %v, %v", fix, err)
+	}
+}
+
+// makeOrgWithClaudeMD creates an org whose brand repo has the given org
+// CLAUDE.md and a sibling repo "toyon".
+func makeOrgWithClaudeMD(t *testing.T, content string) config.Org {
+	t.Helper()
+	org := makeOrg(t, nil)
+	org.Manifest.ClaudeMD = "org/CLAUDE.md"
+	org.Repos = []string{"toyon"}
+	if err := os.MkdirAll(filepath.Join(org.Path, "toyon"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(org.Path, "brand", "skills"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeOrgFile(t, org, "brand/org/CLAUDE.md", content)
+	return org
+}
+
+func writeOrgFile(t *testing.T, org config.Org, rel, content string) {
+	t.Helper()
+	path := filepath.Join(org.Path, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateClaudeMD(t *testing.T) {
+	org := makeOrgWithClaudeMD(t, "# Manzanita\n\nSee @voice.md and @docs/missing.md.\nMail me@example.com or ping @alice.\n\n```\n@not/an/import.md\n```\n")
+	writeOrgFile(t, org, "brand/org/voice.md", "Tone: warm. Also @nested/gone.md\n")
+
+	results, err := ValidateClaudeMD(org)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := findResult(t, results, "CLAUDE.md")
+	assertHasError(t, r, `CLAUDE.md imports missing file "docs/missing.md"`)
+	assertHasError(t, r, `voice.md imports missing file "nested/gone.md"`)
+	if len(r.Errors) != 2 {
+		t.Errorf("expected 2 errors, got %v", r.Errors)
+	}
+}
+
+func TestValidateClaudeMD_SeparateFromSkills(t *testing.T) {
+	org := makeOrgWithClaudeMD(t, "# Org\n\nSee @missing.md.\n")
+
+	results, err := ValidateOrg(org)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no results for an org without skills, got %v", results)
+	}
+
+	org.Manifest.ClaudeMD = ""
+	if results, err := ValidateClaudeMD(org); err != nil || results != nil {
+		t.Errorf("expected nothing to check without claude_md, got %v, %v", results, err)
+	}
+}
+
+func TestValidateClaudeMD_MissingAndBudget(t *testing.T) {
+	org := makeOrgWithClaudeMD(t, "")
+	org.Manifest.ClaudeMD = "org/NOPE.md"
+	results, _ := ValidateClaudeMD(org)
+	assertHasError(t, findResult(t, results, "CLAUDE.md"), "org CLAUDE.md not found at org/NOPE.md")
+
+	org = makeOrgWithClaudeMD(t, "   \n")
+	results, _ = ValidateClaudeMD(org)
+	assertHasWarning(t, findResult(t, results, "CLAUDE.md"), "org CLAUDE.md is empty")
+
+	org = makeOrgWithClaudeMD(t, strings.Repeat("x", 200))
+	org.Manifest.Validation.ClaudeMDBudget = 100
+	results, _ = ValidateClaudeMD(org)
+	assertHasWarning(t, findResult(t, results, "CLAUDE.md"), "org CLAUDE.md is 200 bytes (budget 100)")
+}
+
+func TestValidateClaudeMD_OrgOwnedSections(t *testing.T) {
+	org := makeOrgWithClaudeMD(t, "# Org\n\n## Voice <!-- org-owned -->\nWarm.\n\n## Security\n<!-- org-owned -->\nNo secrets.\n\n## Testing\nUp to you.\n")
+	writeOrgFile(t, org, "toyon/CLAUDE.md", "# Toyon\n\n## voice\nCasual.\n\n## Testing\nUse go test.\n")
+	writeOrgFile(t, org, "toyon/.claude/CLAUDE.md", "### Security\nWhatever.\n")
+
+	results, err := ValidateClaudeMD(org)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := findResult(t, results, "toyon/CLAUDE.md")
+	assertHasWarning(t, r, `CLAUDE.md redefines org-owned section "Voice"`)
+	assertHasWarning(t, r, `.claude/CLAUDE.md redefines org-owned section "Security"`)
+	if len(r.Warnings) != 2 {
+		t.Errorf("Testing isn't org-owned, expected 2 warnings, got %v", r.Warnings)
+	}
+}
+
+func TestValidateOrg_DescriptionOverlap(t *testing.T) {
+	org := makeOrg(t, map[string]string{
+		"code-review":   "---\nname: code-review\ndescription: Review Go code for bugs and style issues\nlicense: MIT\n---\n",
+		"go-review":     "---\nname: go-review\ndescription: Review Go code for bugs and style problems\nlicense: MIT\n---\n",
+		"release-notes": "---\nname: release-notes\ndescription: Write release notes from merged pull requests. Use when cutting a release.\nlicense: MIT\n---\n",
+	})
+
+	results, err := ValidateOrg(org)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := findFinding(findResult(t, results, "code-review"), "description-overlap"); !ok {
+		t.Error("expected code-review to overlap with go-review")
+	}
+	if f, ok := findFinding(findResult(t, results, "go-review"), "description-overlap"); !ok || !strings.Contains(f.Message, `"code-review"`) {
+		t.Errorf("expected go-review to overlap with code-review, got %v", f)
+	}
+	if r := findResult(t, results, "release-notes"); len(r.Warnings) != 0 {
+		t.Errorf("release-notes shouldn't overlap, got %v", r.Warnings)
+	}
+
+	org.Manifest.Validation.IgnoreOverlap = [][]string{{"go-review", "code-review"}}
+	results, err = ValidateOrg(org)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if _, ok := findFinding(r, "description-overlap"); ok {
+			t.Errorf("%s: ignored pair still reported", r.Skill)
+		}
+	}
+}